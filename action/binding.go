@@ -0,0 +1,48 @@
+package action
+
+import (
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+)
+
+// Source is the kind of input of a binding
+type Source byte
+
+const (
+	KEY            Source = 0
+	MOUSE_BUTTON   Source = 1
+	GAMEPAD_BUTTON Source = 2
+	GAMEPAD_AXIS   Source = 3
+)
+
+// Binding connects an input to an action. Buttons and keys contribute Scale to the action's value while held, and axes
+// contribute their value multiplied by Scale (e.g. a key bound with scale -1 and another with scale 1 make an axis).
+type Binding struct {
+	Source Source
+	//The key.Key, cursor.Button, gamepad.Button or gamepad.Axis, according to the source
+	Code  int
+	Scale float32
+}
+
+func Key(k key.Key) Binding {
+	return Binding{Source: KEY, Code: int(k), Scale: 1}
+}
+
+func MouseButton(button cursor.Button) Binding {
+	return Binding{Source: MOUSE_BUTTON, Code: int(button), Scale: 1}
+}
+
+func GamepadButton(button gamepad.Button) Binding {
+	return Binding{Source: GAMEPAD_BUTTON, Code: int(button), Scale: 1}
+}
+
+func GamepadAxis(axis gamepad.Axis) Binding {
+	return Binding{Source: GAMEPAD_AXIS, Code: int(axis), Scale: 1}
+}
+
+// Scaled returns a copy of the binding with another scale.
+func (b Binding) Scaled(scale float32) Binding {
+	b.Scale = scale
+	return b
+}
@@ -0,0 +1,131 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"io"
+	"os"
+)
+
+// Representation of a binding in the bindings file
+type bindingJson struct {
+	Source string `json:"source"`
+	Code   int    `json:"code"`
+	//1 when absent, like the bindings created in code
+	Scale *float32 `json:"scale,omitempty"`
+}
+
+var sourceNames = map[Source]string{
+	KEY:            "key",
+	MOUSE_BUTTON:   "mouse_button",
+	GAMEPAD_BUTTON: "gamepad_button",
+	GAMEPAD_AXIS:   "gamepad_axis",
+}
+
+// Save writes the bindings as JSON, with each action mapped to a list of bindings. The codes are the values of the
+// key.Key, cursor.Button, gamepad.Button and gamepad.Axis constants.
+func (m *Map) Save(w io.Writer) error {
+	actions := make(map[string][]bindingJson, len(m.bindings))
+	for action, bindings := range m.bindings {
+		list := make([]bindingJson, 0, len(bindings))
+		for _, binding := range bindings {
+			scale := binding.Scale
+			list = append(list, bindingJson{Source: sourceNames[binding.Source], Code: binding.Code, Scale: &scale})
+		}
+		actions[action] = list
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(actions)
+}
+
+// Load replaces the bindings of the actions present in the JSON written by Save. Actions that aren't in it keep their
+// bindings, so new actions added to the game still have their defaults with an old file. Bindings without a scale have
+// a scale of 1, and the ones with a code that doesn't exist for their source make the whole file invalid.
+func (m *Map) Load(r io.Reader) error {
+	var actions map[string][]bindingJson
+	if err := json.NewDecoder(r).Decode(&actions); err != nil {
+		return fmt.Errorf("invalid bindings: %w", err)
+	}
+
+	loaded := make(map[string][]Binding, len(actions))
+	for action, list := range actions {
+		bindings := make([]Binding, 0, len(list))
+		for _, b := range list {
+			source, err := parseSource(b.Source)
+			if err != nil {
+				return fmt.Errorf("invalid binding for action %q: %w", action, err)
+			}
+			if !validCode(source, b.Code) {
+				return fmt.Errorf("invalid binding for action %q: unknown %s %d", action, b.Source, b.Code)
+			}
+
+			scale := float32(1)
+			if b.Scale != nil {
+				scale = *b.Scale
+			}
+			bindings = append(bindings, Binding{Source: source, Code: b.Code, Scale: scale})
+		}
+		loaded[action] = bindings
+	}
+
+	//Only change the map once the whole file is known to be valid
+	for action, bindings := range loaded {
+		m.bindings[action] = bindings
+	}
+	return nil
+}
+
+func (m *Map) SaveFile(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create bindings file %q: %w", file, err)
+	}
+
+	if err := m.Save(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save bindings file %q: %w", file, err)
+	}
+	return f.Close()
+}
+
+func (m *Map) LoadFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open bindings file %q: %w", file, err)
+	}
+	defer f.Close()
+
+	if err := m.Load(f); err != nil {
+		return fmt.Errorf("failed to load bindings file %q: %w", file, err)
+	}
+	return nil
+}
+
+// Checks that the code is one of the constants of the source's type
+func validCode(source Source, code int) bool {
+	switch source {
+	case KEY:
+		return code > int(key.UNKNOWN) && code <= int(key.MENU)
+	case MOUSE_BUTTON:
+		return code >= 0 && code <= int(cursor.BUTTON_8)
+	case GAMEPAD_BUTTON:
+		return code >= 0 && code < gamepad.ButtonCount
+	case GAMEPAD_AXIS:
+		return code >= 0 && code < gamepad.AxisCount
+	}
+	return false
+}
+
+func parseSource(name string) (Source, error) {
+	for source, sourceName := range sourceNames {
+		if sourceName == name {
+			return source, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown source %q", name)
+}
@@ -0,0 +1,111 @@
+package action
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"strings"
+	"testing"
+)
+
+func TestLoadCodes(t *testing.T) {
+	tests := []struct {
+		source string
+		code   int
+		valid  bool
+	}{
+		{"key", int(key.SPACE), true},
+		{"key", int(key.MENU), true},
+		{"key", int(key.UNKNOWN), false},
+		{"key", int(key.MENU) + 1, false},
+		{"key", 200, false},
+		{"key", 256, false},
+		{"key", -1, false},
+		{"mouse_button", int(cursor.LEFT), true},
+		{"mouse_button", int(cursor.BUTTON_8), true},
+		{"mouse_button", int(cursor.BUTTON_8) + 1, false},
+		{"gamepad_button", int(gamepad.A), true},
+		{"gamepad_button", gamepad.ButtonCount - 1, true},
+		{"gamepad_button", gamepad.ButtonCount, false},
+		{"gamepad_axis", int(gamepad.LEFT_X), true},
+		{"gamepad_axis", gamepad.AxisCount - 1, true},
+		{"gamepad_axis", gamepad.AxisCount, false},
+		{"gamepad_axis", -1, false},
+		{"joystick", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s %d", test.source, test.code), func(t *testing.T) {
+			m := NewMap()
+			m.Bind("jump", Key(key.SPACE), GamepadButton(gamepad.A))
+			m.Bind("fire", MouseButton(cursor.LEFT))
+
+			//The valid binding of another action isn't applied if the file has an invalid one
+			file := fmt.Sprintf(`{"fire": [{"source": "key", "code": %d}], "jump": [{"source": %q, "code": %d}]}`,
+				key.ENTER, test.source, test.code)
+			err := m.Load(strings.NewReader(file))
+			if (err == nil) != test.valid {
+				t.Fatalf("Load returned %v, want valid = %v", err, test.valid)
+			}
+
+			fire := m.Bindings("fire")
+			if test.valid != (len(fire) == 1 && fire[0] == Key(key.ENTER)) {
+				t.Errorf("fire is bound to %v after loading a file with valid = %v", fire, test.valid)
+			}
+		})
+	}
+}
+
+func TestLoadScale(t *testing.T) {
+	tests := []struct {
+		json  string
+		scale float32
+	}{
+		{`{"source": "key", "code": 19}`, 1},
+		{`{"source": "key", "code": 19, "scale": -1}`, -1},
+		{`{"source": "gamepad_axis", "code": 0, "scale": 0.5}`, 0.5},
+		{`{"source": "gamepad_axis", "code": 0, "scale": 0}`, 0},
+	}
+
+	for _, test := range tests {
+		m := NewMap()
+		if err := m.Load(strings.NewReader(`{"move_x": [` + test.json + `]}`)); err != nil {
+			t.Fatal(err)
+		}
+		if bindings := m.Bindings("move_x"); len(bindings) != 1 || bindings[0].Scale != test.scale {
+			t.Errorf("%s loaded as %v, want scale %v", test.json, bindings, test.scale)
+		}
+	}
+}
+
+// A file saved by an older version of the game doesn't remove the defaults of the actions added since then
+func TestLoadKeepsNewActions(t *testing.T) {
+	old := NewMap()
+	old.Bind("jump", Key(key.W))
+	old.Bind("move_x", Key(key.A).Scaled(-1), Key(key.D), GamepadAxis(gamepad.LEFT_X))
+
+	var file bytes.Buffer
+	if err := old.Save(&file); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMap()
+	m.Bind("jump", Key(key.SPACE))
+	m.Bind("dash", Key(key.LEFT_SHIFT), GamepadButton(gamepad.B))
+	if err := m.Load(&file); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]Binding{
+		"jump":   old.Bindings("jump"),
+		"move_x": old.Bindings("move_x"),
+		"dash":   {Key(key.LEFT_SHIFT), GamepadButton(gamepad.B)},
+	}
+	for action, bindings := range want {
+		if fmt.Sprint(m.Bindings(action)) != fmt.Sprint(bindings) {
+			t.Errorf("%s is bound to %v, want %v", action, m.Bindings(action), bindings)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package action
+
+import (
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"math"
+	"sort"
+)
+
+// Map associates named actions (such as "jump" or "move_x") with the inputs that trigger them, so the game logic doesn't
+// depend on specific keys or buttons and the player can rebind them.
+//
+// The Map implements the key, cursor and gamepad listeners, so it can be embedded in a state to receive the input
+// events from the game. Update must be called once per frame (usually at the start of the state's Update); the values
+// reported by the Map only change then, so they are stable for the whole frame.
+type Map struct {
+	bindings map[string][]Binding
+
+	//Digital inputs currently down, and the ones pressed since the last update (so that presses shorter than a frame are
+	//not lost)
+	down    map[Binding]bool
+	latched map[Binding]bool
+	//Axes of each connected gamepad
+	axes map[gamepad.ID]*[gamepad.AxisCount]float32
+	//Gamepad buttons are tracked per gamepad, as the same button may be down on more than one
+	gamepadButtons map[gamepad.ID]*[gamepad.ButtonCount]bool
+
+	values         map[string]float32
+	previousValues map[string]float32
+}
+
+func NewMap() *Map {
+	return &Map{
+		bindings:       make(map[string][]Binding),
+		down:           make(map[Binding]bool),
+		latched:        make(map[Binding]bool),
+		axes:           make(map[gamepad.ID]*[gamepad.AxisCount]float32),
+		gamepadButtons: make(map[gamepad.ID]*[gamepad.ButtonCount]bool),
+		values:         make(map[string]float32),
+		previousValues: make(map[string]float32),
+	}
+}
+
+// Bind replaces the bindings of an action.
+func (m *Map) Bind(action string, bindings ...Binding) {
+	m.bindings[action] = append([]Binding(nil), bindings...)
+}
+
+// AddBinding adds a binding to an action, keeping the existing ones.
+func (m *Map) AddBinding(action string, binding Binding) {
+	m.bindings[action] = append(m.bindings[action], binding)
+}
+
+func (m *Map) Unbind(action string) {
+	delete(m.bindings, action)
+}
+
+func (m *Map) Bindings(action string) []Binding {
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of all the actions with bindings, sorted.
+func (m *Map) Actions() []string {
+	actions := make([]string, 0, len(m.bindings))
+	for action := range m.bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Update calculates the values of the actions from the input received since the last call.
+func (m *Map) Update() {
+	m.previousValues, m.values = m.values, m.previousValues
+	for action := range m.values {
+		delete(m.values, action)
+	}
+
+	for action, bindings := range m.bindings {
+		var value float32
+		for _, binding := range bindings {
+			value += m.bindingValue(binding)
+		}
+		m.values[action] = clamp(value)
+	}
+
+	for binding := range m.latched {
+		delete(m.latched, binding)
+	}
+}
+
+// Value returns the analog value of an action, from -1 to 1 (or from 0 to 1 if all its bindings have positive scales).
+func (m *Map) Value(action string) float32 {
+	return m.values[action]
+}
+
+// Held returns whether the action is active in this frame.
+func (m *Map) Held(action string) bool {
+	return m.values[action] != 0
+}
+
+// Pressed returns whether the action became active in this frame.
+func (m *Map) Pressed(action string) bool {
+	return m.values[action] != 0 && m.previousValues[action] == 0
+}
+
+// Released returns whether the action stopped being active in this frame.
+func (m *Map) Released(action string) bool {
+	return m.values[action] == 0 && m.previousValues[action] != 0
+}
+
+func (m *Map) KeyEvent(event key.Event) {
+	switch event.Action {
+	case key.PRESS:
+		m.press(Key(event.Key))
+	case key.RELEASE:
+		m.release(Key(event.Key))
+	}
+}
+
+func (m *Map) ButtonPressed(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	m.press(MouseButton(button))
+}
+
+func (m *Map) ButtonReleased(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	m.release(MouseButton(button))
+}
+
+func (m *Map) GamepadConnected(id gamepad.ID, name string) {
+	m.ensureGamepad(id)
+}
+
+func (m *Map) GamepadDisconnected(id gamepad.ID) {
+	delete(m.axes, id)
+	delete(m.gamepadButtons, id)
+	m.updateGamepadButtons()
+}
+
+func (m *Map) GamepadButtonPressed(id gamepad.ID, button gamepad.Button) {
+	m.ensureGamepad(id)
+	m.gamepadButtons[id][button] = true
+	m.latched[GamepadButton(button)] = true
+	m.updateGamepadButtons()
+}
+
+func (m *Map) GamepadButtonReleased(id gamepad.ID, button gamepad.Button) {
+	m.ensureGamepad(id)
+	m.gamepadButtons[id][button] = false
+	m.updateGamepadButtons()
+}
+
+func (m *Map) GamepadAxisMoved(id gamepad.ID, axis gamepad.Axis, value float32) {
+	m.ensureGamepad(id)
+	m.axes[id][axis] = value
+}
+
+// The gamepad may have been connected before the Map started receiving events
+func (m *Map) ensureGamepad(id gamepad.ID) {
+	if _, isKnown := m.axes[id]; !isKnown {
+		m.axes[id] = &[gamepad.AxisCount]float32{}
+		m.gamepadButtons[id] = &[gamepad.ButtonCount]bool{}
+	}
+}
+
+func (m *Map) press(binding Binding) {
+	m.down[binding] = true
+	m.latched[binding] = true
+}
+
+func (m *Map) release(binding Binding) {
+	delete(m.down, binding)
+}
+
+// A gamepad button is down if it is down on any of the connected gamepads
+func (m *Map) updateGamepadButtons() {
+	for button := gamepad.Button(0); button < gamepad.ButtonCount; button++ {
+		delete(m.down, GamepadButton(button))
+		for _, buttons := range m.gamepadButtons {
+			if buttons[button] {
+				m.down[GamepadButton(button)] = true
+			}
+		}
+	}
+}
+
+func (m *Map) bindingValue(binding Binding) float32 {
+	if binding.Source == GAMEPAD_AXIS {
+		if !validCode(GAMEPAD_AXIS, binding.Code) {
+			return 0
+		}
+
+		//When more than one gamepad is connected, the one furthest from the rest position wins
+		var value float32
+		for _, axes := range m.axes {
+			axisValue := axes[binding.Code]
+			if math.Abs(float64(axisValue)) > math.Abs(float64(value)) {
+				value = axisValue
+			}
+		}
+		return value * binding.Scale
+	}
+
+	//Bindings are compared without the scale to find out whether the input is down
+	input := Binding{Source: binding.Source, Code: binding.Code, Scale: 1}
+	if m.down[input] || m.latched[input] {
+		return binding.Scale
+	}
+	return 0
+}
+
+func clamp(value float32) float32 {
+	if value < -1 {
+		return -1
+	} else if value > 1 {
+		return 1
+	}
+	return value
+}
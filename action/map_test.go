@@ -0,0 +1,94 @@
+package action
+
+import (
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"testing"
+)
+
+func TestMapValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input func(m *Map)
+		want  float32
+	}{
+		{"nothing held", func(m *Map) {}, 0},
+		{"negative key", func(m *Map) { m.KeyEvent(key.Event{Key: key.A, Action: key.PRESS}) }, -1},
+		{"positive key", func(m *Map) { m.KeyEvent(key.Event{Key: key.D, Action: key.PRESS}) }, 1},
+		{"opposite keys cancel out", func(m *Map) {
+			m.KeyEvent(key.Event{Key: key.A, Action: key.PRESS})
+			m.KeyEvent(key.Event{Key: key.D, Action: key.PRESS})
+		}, 0},
+		{"key and axis are clamped", func(m *Map) {
+			m.KeyEvent(key.Event{Key: key.D, Action: key.PRESS})
+			m.GamepadAxisMoved(0, gamepad.LEFT_X, 1)
+		}, 1},
+		{"scaled axis", func(m *Map) { m.GamepadAxisMoved(0, gamepad.LEFT_X, -0.8) }, -0.4},
+		{"axis furthest from rest wins", func(m *Map) {
+			m.GamepadAxisMoved(0, gamepad.LEFT_X, 0.2)
+			m.GamepadAxisMoved(1, gamepad.LEFT_X, -0.6)
+		}, -0.3},
+		{"disconnected gamepad", func(m *Map) {
+			m.GamepadAxisMoved(0, gamepad.LEFT_X, 1)
+			m.GamepadDisconnected(0)
+		}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMap()
+			m.Bind("move_x", Key(key.A).Scaled(-1), Key(key.D), GamepadAxis(gamepad.LEFT_X).Scaled(0.5))
+			test.input(m)
+			m.Update()
+
+			if got := m.Value("move_x"); got != test.want {
+				t.Errorf("value = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMapEdges(t *testing.T) {
+	m := NewMap()
+	m.Bind("fire", MouseButton(cursor.LEFT), GamepadButton(gamepad.A))
+
+	steps := []struct {
+		name                    string
+		input                   func()
+		held, pressed, released bool
+	}{
+		{"idle", func() {}, false, false, false},
+		{"press", func() { m.ButtonPressed(cursor.LEFT, 0, 0, 0) }, true, true, false},
+		{"hold", func() {}, true, false, false},
+		{"second binding held too", func() { m.GamepadButtonPressed(0, gamepad.A) }, true, false, false},
+		{"release one", func() { m.ButtonReleased(cursor.LEFT, 0, 0, 0) }, true, false, false},
+		{"release all", func() { m.GamepadButtonReleased(0, gamepad.A) }, false, false, true},
+		{"press and release between updates", func() {
+			m.ButtonPressed(cursor.LEFT, 0, 0, 0)
+			m.ButtonReleased(cursor.LEFT, 0, 0, 0)
+		}, true, true, false},
+		{"after the short press", func() {}, false, false, true},
+	}
+
+	for _, step := range steps {
+		step.input()
+		m.Update()
+
+		if m.Held("fire") != step.held || m.Pressed("fire") != step.pressed || m.Released("fire") != step.released {
+			t.Errorf("%s: held %v, pressed %v, released %v; want %v, %v, %v", step.name, m.Held("fire"),
+				m.Pressed("fire"), m.Released("fire"), step.held, step.pressed, step.released)
+		}
+	}
+}
+
+func TestMapIgnoresInvalidAxis(t *testing.T) {
+	m := NewMap()
+	m.Bind("look", Binding{Source: GAMEPAD_AXIS, Code: int(gamepad.AxisCount), Scale: 1})
+	m.GamepadAxisMoved(0, gamepad.LEFT_X, 1)
+	m.Update()
+
+	if m.Value("look") != 0 {
+		t.Errorf("value = %v, want 0", m.Value("look"))
+	}
+}
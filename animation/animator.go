@@ -0,0 +1,153 @@
+package animation
+
+import (
+	"github.com/Hikarikun92/go-game-engine/sprite"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"time"
+)
+
+// Listener is notified of the events of the frames shown by an Animator and of the end of ONCE clips.
+type Listener interface {
+	FrameEvent(clip *Clip, event string)
+	ClipFinished(clip *Clip)
+}
+
+// Animator plays clips of a sprite sheet, advanced by the delta passed to the state's Update.
+type Animator struct {
+	sheet    *sprite.Sheet
+	listener Listener
+
+	clip *Clip
+	//Position of the current frame in the clip
+	position int
+	//1 or -1, only going backwards in PING_PONG clips
+	step int
+	//Time the current frame has been shown
+	elapsed  time.Duration
+	finished bool
+}
+
+func NewAnimator(sheet *sprite.Sheet) *Animator {
+	return &Animator{sheet: sheet, step: 1}
+}
+
+func (a *Animator) SetListener(listener Listener) {
+	a.listener = listener
+}
+
+// Play starts a clip from its first frame, unless it is already playing (so it can be called every update, e.g. with the
+// clip matching the character's movement).
+func (a *Animator) Play(clip *Clip) {
+	if clip == a.clip && !a.finished {
+		return
+	}
+	a.Restart(clip)
+}
+
+// Restart starts a clip from its first frame, even if it is already playing. A nil clip stops the animation.
+func (a *Animator) Restart(clip *Clip) {
+	a.clip = clip
+	a.position = 0
+	a.step = 1
+	a.elapsed = 0
+	a.finished = false
+
+	if a.playing() {
+		a.frameStarted()
+	}
+}
+
+// Clip returns the clip being played, or nil if none was played yet.
+func (a *Animator) Clip() *Clip {
+	return a.clip
+}
+
+// Finished returns whether a ONCE clip reached the end of its last frame.
+func (a *Animator) Finished() bool {
+	return a.finished
+}
+
+// Position returns the position of the current frame in the clip.
+func (a *Animator) Position() int {
+	return a.position
+}
+
+// Frame returns the index in the sprite sheet of the current frame, or -1 if no clip is playing or it has no frames.
+func (a *Animator) Frame() int {
+	if !a.playing() {
+		return -1
+	}
+	return a.clip.Frames[a.position].Index
+}
+
+// Update advances the clip, going through as many frames as needed (and triggering their events) if the delta is longer
+// than the current frame.
+func (a *Animator) Update(delta time.Duration) {
+	if a.clip == nil || a.finished || a.clip.Duration() <= 0 {
+		return
+	}
+
+	a.elapsed += delta
+	for a.elapsed >= a.clip.Frames[a.position].Duration {
+		a.elapsed -= a.clip.Frames[a.position].Duration
+		if !a.advance() {
+			a.elapsed = 0
+			a.finished = true
+			if a.listener != nil {
+				a.listener.ClipFinished(a.clip)
+			}
+			return
+		}
+		a.frameStarted()
+	}
+}
+
+// Draw draws the current frame with the bottom left corner of its original image at the given position.
+func (a *Animator) Draw(graphics ui.Graphics, x int, y int) {
+	if a.playing() {
+		a.sheet.Draw(graphics, a.Frame(), x, y)
+	}
+}
+
+// DrawOptions draws the current frame transformed by the options.
+func (a *Animator) DrawOptions(graphics ui.Graphics, options ui.DrawOptions) {
+	if a.playing() {
+		a.sheet.DrawOptions(graphics, a.Frame(), options)
+	}
+}
+
+// Whether there's a current frame
+func (a *Animator) playing() bool {
+	return a.clip != nil && len(a.clip.Frames) > 0
+}
+
+// Moves to the next frame according to the clip's mode, returning false if there's no next frame
+func (a *Animator) advance() bool {
+	last := len(a.clip.Frames) - 1
+
+	switch a.clip.Mode {
+	case PING_PONG:
+		if last == 0 {
+			return true
+		}
+		if a.position+a.step < 0 || a.position+a.step > last {
+			a.step = -a.step
+		}
+		a.position += a.step
+	case ONCE:
+		if a.position == last {
+			return false
+		}
+		a.position++
+	default:
+		a.position = (a.position + 1) % len(a.clip.Frames)
+	}
+	return true
+}
+
+func (a *Animator) frameStarted() {
+	event := a.clip.Frames[a.position].Event
+	if event != "" && a.listener != nil {
+		a.listener.FrameEvent(a.clip, event)
+	}
+}
@@ -0,0 +1,137 @@
+package animation
+
+import (
+	"github.com/Hikarikun92/go-game-engine/sprite"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"image"
+	"testing"
+	"time"
+)
+
+type recordingListener struct {
+	events   []string
+	finished int
+}
+
+func (l *recordingListener) FrameEvent(clip *Clip, event string) {
+	l.events = append(l.events, event)
+}
+
+func (l *recordingListener) ClipFinished(clip *Clip) {
+	l.finished++
+}
+
+func newSheet() *sprite.Sheet {
+	img := headless.NewImage(image.NewRGBA(image.Rect(0, 0, 64, 16)))
+	return sprite.NewGridSheet(img, sprite.Grid{FrameWidth: 16, FrameHeight: 16})
+}
+
+func TestAnimatorModes(t *testing.T) {
+	const frameDuration = 100 * time.Millisecond
+
+	tests := []struct {
+		name string
+		mode Mode
+		//Index of the frame shown after each update of one frame's duration
+		frames   []int
+		finished bool
+	}{
+		{"loop", LOOP, []int{1, 2, 0, 1, 2, 0}, false},
+		{"ping-pong", PING_PONG, []int{1, 2, 1, 0, 1, 2}, false},
+		{"once", ONCE, []int{1, 2, 2, 2, 2, 2}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			animator := NewAnimator(newSheet())
+			listener := &recordingListener{}
+			animator.SetListener(listener)
+			animator.Play(NewClip(test.name, test.mode, frameDuration, 0, 1, 2))
+
+			if frame := animator.Frame(); frame != 0 {
+				t.Fatalf("started at frame %d, want 0", frame)
+			}
+			for i, want := range test.frames {
+				animator.Update(frameDuration)
+				if frame := animator.Frame(); frame != want {
+					t.Fatalf("frame after update %d = %d, want %d", i, frame, want)
+				}
+			}
+
+			if animator.Finished() != test.finished {
+				t.Errorf("finished = %v, want %v", animator.Finished(), test.finished)
+			}
+			if test.finished && listener.finished != 1 {
+				t.Errorf("listener notified of the end %d times, want 1", listener.finished)
+			}
+		})
+	}
+}
+
+func TestAnimatorLongDeltaTriggersSkippedEvents(t *testing.T) {
+	animator := NewAnimator(newSheet())
+	listener := &recordingListener{}
+	animator.SetListener(listener)
+
+	clip := NewClip("walk", LOOP, 100*time.Millisecond, 0, 1, 2, 3)
+	if err := clip.SetEvent(1, "left_step"); err != nil {
+		t.Fatal(err)
+	}
+	if err := clip.SetEvent(3, "right_step"); err != nil {
+		t.Fatal(err)
+	}
+
+	animator.Play(clip)
+	animator.Update(350 * time.Millisecond)
+
+	if animator.Frame() != 3 {
+		t.Errorf("frame = %d, want 3", animator.Frame())
+	}
+	if len(listener.events) != 2 || listener.events[0] != "left_step" || listener.events[1] != "right_step" {
+		t.Errorf("events = %v, want [left_step right_step]", listener.events)
+	}
+}
+
+func TestAnimatorPlayDoesNotRestart(t *testing.T) {
+	animator := NewAnimator(newSheet())
+	clip := NewClip("idle", LOOP, 100*time.Millisecond, 0, 1)
+
+	animator.Play(clip)
+	animator.Update(100 * time.Millisecond)
+	animator.Play(clip)
+	if animator.Frame() != 1 {
+		t.Errorf("Play restarted the clip that was playing")
+	}
+
+	animator.Restart(clip)
+	if animator.Frame() != 0 {
+		t.Errorf("Restart didn't go back to the first frame")
+	}
+}
+
+func TestAnimatorWithoutFrames(t *testing.T) {
+	animator := NewAnimator(newSheet())
+	if animator.Frame() != -1 {
+		t.Errorf("frame without a clip = %d, want -1", animator.Frame())
+	}
+
+	animator.Play(NewClip("empty", LOOP, 100*time.Millisecond))
+	animator.Update(time.Second)
+	if animator.Frame() != -1 {
+		t.Errorf("frame of an empty clip = %d, want -1", animator.Frame())
+	}
+
+	animator.Restart(nil)
+	if animator.Frame() != -1 {
+		t.Errorf("frame after stopping = %d, want -1", animator.Frame())
+	}
+}
+
+func TestClipSetEventOutOfRange(t *testing.T) {
+	clip := NewClip("jump", ONCE, 100*time.Millisecond, 0, 1)
+	for _, position := range []int{-1, 2} {
+		if err := clip.SetEvent(position, "land"); err == nil {
+			t.Errorf("SetEvent(%d) didn't fail", position)
+		}
+	}
+}
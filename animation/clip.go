@@ -0,0 +1,102 @@
+package animation
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/sprite"
+	"time"
+)
+
+// Mode defines what happens when a clip reaches its last frame
+type Mode byte
+
+const (
+	//Starts again from the first frame
+	LOOP Mode = 0
+	//Goes back to the first frame and forth again, without repeating the frames at the ends
+	PING_PONG Mode = 1
+	//Stops at the last frame
+	ONCE Mode = 2
+)
+
+// Duration of the frames that don't have one in the sprite sheet, which is Aseprite's default
+const DefaultFrameDuration = 100 * time.Millisecond
+
+// Frame is a step of a clip.
+type Frame struct {
+	//Index of the frame in the sprite sheet
+	Index    int
+	Duration time.Duration
+	//Name of the event triggered when the frame starts being shown (e.g. a footstep sound), if any
+	Event string
+}
+
+// Clip is a sequence of frames of a sprite sheet, like a character walking or attacking.
+type Clip struct {
+	Name   string
+	Frames []Frame
+	Mode   Mode
+}
+
+// NewClip creates a clip where every frame of the sprite sheet is shown for the same duration.
+func NewClip(name string, mode Mode, duration time.Duration, indices ...int) *Clip {
+	clip := &Clip{Name: name, Mode: mode, Frames: make([]Frame, len(indices))}
+	for i, index := range indices {
+		clip.Frames[i] = Frame{Index: index, Duration: duration}
+	}
+	return clip
+}
+
+// ClipFromTag creates a clip from a tag of the sprite sheet, such as the ones exported by Aseprite, using the duration
+// of each frame in the sheet. Reverse tags play their frames backwards, and ping-pong tags use the PING_PONG mode;
+// the others loop.
+func ClipFromTag(sheet *sprite.Sheet, name string) (*Clip, error) {
+	tag, found := sheet.Tag(name)
+	if !found {
+		return nil, fmt.Errorf("sprite sheet has no tag %q", name)
+	}
+
+	clip := &Clip{Name: name, Mode: LOOP}
+	for index := tag.From; index <= tag.To; index++ {
+		duration := sheet.Frames[index].Duration
+		if duration <= 0 {
+			duration = DefaultFrameDuration
+		}
+		clip.Frames = append(clip.Frames, Frame{Index: index, Duration: duration})
+	}
+
+	switch tag.Direction {
+	case "reverse":
+		clip.reverse()
+	case "pingpong":
+		clip.Mode = PING_PONG
+	case "pingpong_reverse":
+		clip.reverse()
+		clip.Mode = PING_PONG
+	}
+	return clip, nil
+}
+
+// SetEvent makes the frame at the given position of the clip trigger an event when it starts being shown.
+func (c *Clip) SetEvent(position int, event string) error {
+	if position < 0 || position >= len(c.Frames) {
+		return fmt.Errorf("clip %q has no frame at position %d", c.Name, position)
+	}
+
+	c.Frames[position].Event = event
+	return nil
+}
+
+// Duration returns how long the clip takes to show every frame once.
+func (c *Clip) Duration() time.Duration {
+	var total time.Duration
+	for _, frame := range c.Frames {
+		total += frame.Duration
+	}
+	return total
+}
+
+func (c *Clip) reverse() {
+	for i, j := 0, len(c.Frames)-1; i < j; i, j = i+1, j-1 {
+		c.Frames[i], c.Frames[j] = c.Frames[j], c.Frames[i]
+	}
+}
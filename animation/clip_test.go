@@ -0,0 +1,63 @@
+package animation
+
+import (
+	"github.com/Hikarikun92/go-game-engine/sprite"
+	"testing"
+	"time"
+)
+
+func TestClipFromTag(t *testing.T) {
+	sheet := &sprite.Sheet{
+		Frames: []sprite.Frame{{Duration: 50 * time.Millisecond}, {}, {Duration: 200 * time.Millisecond}, {}},
+		Tags: []sprite.Tag{
+			{Name: "forward", From: 0, To: 2, Direction: "forward"},
+			{Name: "reverse", From: 1, To: 3, Direction: "reverse"},
+			{Name: "pingpong", From: 0, To: 1, Direction: "pingpong"},
+			{Name: "pingpong_reverse", From: 2, To: 3, Direction: "pingpong_reverse"},
+		},
+	}
+
+	tests := []struct {
+		tag     string
+		mode    Mode
+		indices []int
+	}{
+		{"forward", LOOP, []int{0, 1, 2}},
+		{"reverse", LOOP, []int{3, 2, 1}},
+		{"pingpong", PING_PONG, []int{0, 1}},
+		{"pingpong_reverse", PING_PONG, []int{3, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			clip, err := ClipFromTag(sheet, test.tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if clip.Mode != test.mode {
+				t.Errorf("mode = %d, want %d", clip.Mode, test.mode)
+			}
+			if len(clip.Frames) != len(test.indices) {
+				t.Fatalf("got %d frames, want %d", len(clip.Frames), len(test.indices))
+			}
+			for i, frame := range clip.Frames {
+				if frame.Index != test.indices[i] {
+					t.Errorf("frame %d has index %d, want %d", i, frame.Index, test.indices[i])
+				}
+
+				//Frames without a duration in the sheet use the default one
+				want := sheet.Frames[frame.Index].Duration
+				if want == 0 {
+					want = DefaultFrameDuration
+				}
+				if frame.Duration != want {
+					t.Errorf("frame %d lasts %v, want %v", i, frame.Duration, want)
+				}
+			}
+		})
+	}
+
+	if _, err := ClipFromTag(sheet, "missing"); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+}
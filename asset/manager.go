@@ -0,0 +1,173 @@
+package asset
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"reflect"
+)
+
+// Manager caches the images loaded from files, so that states using the same file share a single image. Each state
+// (or any other owner) loads images through its own ImageLoader, returned by For, and an image is only unloaded when
+// no owner uses it anymore. Owners are told apart with ==, so they should be unique tokens such as pointers rather
+// than the states themselves: a state that isn't comparable would panic, and two equal states would share references.
+// The cache is keyed by file, and the images are told apart with == when unloaded, so the ui.Image implementations
+// loaded through it must be comparable: loading an image that isn't returns an error.
+type Manager struct {
+	imageLoader ui.ImageLoader
+
+	entries map[string]*cacheEntry
+	owned   map[any]map[string]bool
+}
+
+type cacheEntry struct {
+	image  ui.Image
+	owners map[any]bool
+}
+
+func NewManager(imageLoader ui.ImageLoader) *Manager {
+	return &Manager{
+		imageLoader: imageLoader,
+		entries:     make(map[string]*cacheEntry),
+		owned:       make(map[any]map[string]bool),
+	}
+}
+
+// For returns the ImageLoader to be used by an owner. Loading the same file more than once through it only counts as
+// one reference.
+func (m *Manager) For(owner any) ui.ImageLoader {
+	return &ownedImageLoader{manager: m, owner: owner}
+}
+
+// Release drops every reference of an owner (such as the images a state forgot to unload), unloading the images that
+// aren't used by anyone else.
+func (m *Manager) Release(owner any) {
+	for file := range m.owned[owner] {
+		m.release(owner, file)
+	}
+	delete(m.owned, owner)
+}
+
+// Contains returns whether the image of a file is in the cache.
+func (m *Manager) Contains(file string) bool {
+	_, isCached := m.entries[file]
+	return isCached
+}
+
+// Add puts an image loaded elsewhere (e.g. by a Preload) in the cache without any owner, so the next owner loading that
+// file takes it. If the file is already cached (or the image isn't comparable), the given image is unloaded instead.
+func (m *Manager) Add(file string, img ui.Image) {
+	if m.Contains(file) {
+		m.imageLoader.UnloadImage(img)
+		return
+	}
+
+	if !isComparable(img) {
+		m.imageLoader.UnloadImage(img)
+		return
+	}
+
+	m.entries[file] = &cacheEntry{image: img, owners: make(map[any]bool)}
+}
+
+// Prune unloads the cached images that have no owners.
+func (m *Manager) Prune() {
+	for file, entry := range m.entries {
+		if len(entry.owners) == 0 {
+			m.unload(file)
+		}
+	}
+}
+
+func (m *Manager) acquire(owner any, file string) (ui.Image, error) {
+	entry, isCached := m.entries[file]
+	if !isCached {
+		img, err := m.imageLoader.LoadImage(file)
+		if err != nil {
+			return nil, err
+		}
+		if !isComparable(img) {
+			m.imageLoader.UnloadImage(img)
+			return nil, fmt.Errorf("failed to cache image %q: %T isn't comparable", file, img)
+		}
+
+		entry = &cacheEntry{image: img, owners: make(map[any]bool)}
+		m.entries[file] = entry
+	}
+
+	entry.owners[owner] = true
+
+	files, hasFiles := m.owned[owner]
+	if !hasFiles {
+		files = make(map[string]bool)
+		m.owned[owner] = files
+	}
+	files[file] = true
+
+	return entry.image, nil
+}
+
+func (m *Manager) release(owner any, file string) {
+	delete(m.owned[owner], file)
+
+	entry := m.entries[file]
+	delete(entry.owners, owner)
+	if len(entry.owners) == 0 {
+		m.unload(file)
+	}
+}
+
+func (m *Manager) unload(file string) {
+	entry := m.entries[file]
+	delete(m.entries, file)
+
+	m.imageLoader.UnloadImage(entry.image)
+}
+
+type ownedImageLoader struct {
+	manager *Manager
+	owner   any
+}
+
+func (l *ownedImageLoader) LoadImage(file string) (ui.Image, error) {
+	return l.manager.acquire(l.owner, file)
+}
+
+// CreateImage isn't cached, since there's no file to identify the image
+func (l *ownedImageLoader) CreateImage(img image.Image) (ui.Image, error) {
+	return l.manager.imageLoader.CreateImage(img)
+}
+
+// CreateSubImage isn't cached either; the sub-image is valid while its parent is
+func (l *ownedImageLoader) CreateSubImage(parent ui.Image, region image.Rectangle) (ui.Image, error) {
+	return l.manager.imageLoader.CreateSubImage(parent, region)
+}
+
+// The cached image is found by comparing it with the ones of the owner's files (several files may share an image, like
+// the placeholder of missing images). Images cached for other owners only are left alone.
+func (l *ownedImageLoader) UnloadImage(image ui.Image) {
+	if !isComparable(image) {
+		l.manager.imageLoader.UnloadImage(image)
+		return
+	}
+
+	for file := range l.manager.owned[l.owner] {
+		if l.manager.entries[file].image == image {
+			l.manager.release(l.owner, file)
+			return
+		}
+	}
+	for _, entry := range l.manager.entries {
+		if entry.image == image {
+			return
+		}
+	}
+
+	l.manager.imageLoader.UnloadImage(image)
+}
+
+// The cache compares the images with ==, which panics for types that aren't comparable (e.g. a ui.Image implemented by
+// a struct with a slice)
+func isComparable(image ui.Image) bool {
+	return reflect.TypeOf(image).Comparable()
+}
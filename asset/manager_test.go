@@ -0,0 +1,159 @@
+package asset
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"testing"
+)
+
+// A ui.Image that panics when compared with ==
+type sliceImage struct {
+	pixels []byte
+}
+
+func (i sliceImage) Size() (int, int) {
+	return 1, 1
+}
+
+// A countingLoader whose images aren't comparable
+type sliceLoader struct {
+	*countingLoader
+}
+
+func (l *sliceLoader) LoadImage(file string) (ui.Image, error) {
+	if _, err := l.countingLoader.LoadImage(file); err != nil {
+		return nil, err
+	}
+	return sliceImage{pixels: []byte{0}}, nil
+}
+
+func TestManagerSharesImages(t *testing.T) {
+	loader := newCountingLoader()
+	manager := NewManager(loader)
+	first, second := new(int), new(int)
+
+	a, err := manager.For(first).LoadImage("a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := manager.For(second).LoadImage("a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b || loader.loads["a.png"] != 1 {
+		t.Fatalf("the file was loaded %d times instead of shared", loader.loads["a.png"])
+	}
+
+	//Loading again through the same owner doesn't add a reference
+	if _, err := manager.For(first).LoadImage("a.png"); err != nil {
+		t.Fatal(err)
+	}
+	manager.For(first).UnloadImage(a)
+	if loader.loaded != 1 {
+		t.Fatal("the image was unloaded while the second owner still uses it")
+	}
+
+	manager.For(second).UnloadImage(b)
+	if loader.loaded != 0 || manager.Contains("a.png") {
+		t.Error("the image wasn't unloaded after its last owner")
+	}
+}
+
+func TestManagerRelease(t *testing.T) {
+	loader := newCountingLoader()
+	manager := NewManager(loader)
+	first, second := new(int), new(int)
+
+	for _, file := range []string{"a.png", "b.png"} {
+		if _, err := manager.For(first).LoadImage(file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := manager.For(second).LoadImage("b.png"); err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Release(first)
+	if manager.Contains("a.png") || !manager.Contains("b.png") || loader.loaded != 1 {
+		t.Errorf("after releasing the first owner, %d images are loaded, want only b.png", loader.loaded)
+	}
+
+	manager.Release(second)
+	if loader.loaded != 0 {
+		t.Errorf("%d images are still loaded", loader.loaded)
+	}
+}
+
+func TestManagerErrorsAreNotCached(t *testing.T) {
+	loader := newCountingLoader()
+	manager := NewManager(loader)
+
+	if _, err := manager.For(new(int)).LoadImage("missing.png"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if manager.Contains("missing.png") {
+		t.Error("the failed file was cached")
+	}
+}
+
+func TestManagerAddAndPrune(t *testing.T) {
+	loader := newCountingLoader()
+	manager := NewManager(loader)
+
+	added := &fakeImage{file: "a.png"}
+	loader.loaded++
+	manager.Add("a.png", added)
+
+	//A second image for the same file is unloaded
+	loader.loaded++
+	manager.Add("a.png", &fakeImage{file: "a.png"})
+	if loader.loaded != 1 {
+		t.Fatalf("%d images are loaded, want 1", loader.loaded)
+	}
+
+	owner := new(int)
+	img, err := manager.For(owner).LoadImage("a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img != added || loader.loads["a.png"] != 0 {
+		t.Error("the added image wasn't used")
+	}
+
+	manager.Prune()
+	if !manager.Contains("a.png") {
+		t.Error("an image in use was pruned")
+	}
+
+	manager.Release(owner)
+	manager.Add("b.png", &fakeImage{file: "b.png"})
+	loader.loaded++
+	manager.Prune()
+	if manager.Contains("b.png") || loader.loaded != 0 {
+		t.Error("the image without owners wasn't pruned")
+	}
+}
+
+func TestManagerRejectsImagesThatAreNotComparable(t *testing.T) {
+	loader := &sliceLoader{countingLoader: newCountingLoader()}
+	manager := NewManager(loader)
+	owner := new(int)
+
+	if _, err := manager.For(owner).LoadImage("a.png"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if manager.Contains("a.png") || loader.loaded != 0 {
+		t.Errorf("the image was kept: cached %v, %d loaded", manager.Contains("a.png"), loader.loaded)
+	}
+
+	loader.loaded++ //Loaded somewhere else, like the images of a Preload
+	manager.Add("b.png", sliceImage{})
+	if manager.Contains("b.png") || loader.loaded != 0 {
+		t.Error("an image that isn't comparable was added to the cache")
+	}
+
+	//Nothing was left behind for the owner
+	manager.Release(owner)
+	if loader.loaded != 0 {
+		t.Errorf("%d images are still loaded", loader.loaded)
+	}
+}
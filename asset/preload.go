@@ -0,0 +1,128 @@
+package asset
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"runtime"
+)
+
+// Preload decodes a set of image files on background goroutines. Decoding doesn't need the window, but creating the
+// images does, so the decoded files are only turned into ui.Image values when Upload is called from the game loop.
+type Preload struct {
+	total   int
+	decoded chan decodedImage
+
+	images map[string]ui.Image
+	//Files that couldn't be decoded or uploaded and weren't replaced by a placeholder
+	failed int
+}
+
+type decodedImage struct {
+	file  string
+	image image.Image
+	err   error
+}
+
+// StartPreload starts decoding the files using the given amount of goroutines (or one per CPU if workers is not
+// positive). Duplicate files are only decoded once.
+func StartPreload(files []string, workers int) *Preload {
+	unique := make([]string, 0, len(files))
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			unique = append(unique, file)
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+
+	p := &Preload{
+		total: len(unique),
+		//Buffered so the workers never wait for the game loop (and don't leak if the preload is abandoned)
+		decoded: make(chan decodedImage, len(unique)),
+		images:  make(map[string]ui.Image, len(unique)),
+	}
+
+	pending := make(chan string, len(unique))
+	for _, file := range unique {
+		pending <- file
+	}
+	close(pending)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for file := range pending {
+				img, err := ui.DecodeImage(file)
+				p.decoded <- decodedImage{file: file, image: img, err: err}
+			}
+		}()
+	}
+
+	return p
+}
+
+// Upload creates the images for all the files decoded so far, without waiting for the others. It must be called from
+// the goroutine running the game loop. If the loader is a ui.MissingImageReplacer, the files that failed get its
+// placeholder; otherwise they are left out, so loading them later returns the error.
+func (p *Preload) Upload(imageLoader ui.ImageLoader) {
+	for {
+		select {
+		case decoded := <-p.decoded:
+			err := decoded.err
+			if err == nil {
+				var img ui.Image
+				if img, err = imageLoader.CreateImage(decoded.image); err == nil {
+					p.images[decoded.file] = img
+					continue
+				}
+			}
+
+			p.fail(imageLoader, decoded.file, err)
+		default:
+			return
+		}
+	}
+}
+
+func (p *Preload) fail(imageLoader ui.ImageLoader, file string, err error) {
+	replacer, isReplacer := imageLoader.(ui.MissingImageReplacer)
+	if isReplacer {
+		if img, err := replacer.ReplaceMissingImage(file, err); err == nil {
+			p.images[file] = img
+			return
+		}
+	}
+	p.failed++
+}
+
+// Progress returns how many files were already uploaded (or failed) and how many there are in total.
+func (p *Preload) Progress() (int, int) {
+	return len(p.images) + p.failed, p.total
+}
+
+func (p *Preload) Finished() bool {
+	done, total := p.Progress()
+	return done == total
+}
+
+// Store moves the preloaded images to the cache of a Manager, so the next owners loading those files take them.
+func (p *Preload) Store(manager *Manager) {
+	for file, img := range p.images {
+		manager.Add(file, img)
+		delete(p.images, file)
+	}
+}
+
+// Release unloads the preloaded images that weren't stored, e.g. when the preload is abandoned.
+func (p *Preload) Release(imageLoader ui.ImageLoader) {
+	for file, img := range p.images {
+		imageLoader.UnloadImage(img)
+		delete(p.images, file)
+	}
+}
@@ -0,0 +1,134 @@
+package asset
+
+import (
+	"errors"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeImage struct {
+	file string
+}
+
+func (i *fakeImage) Size() (int, int) {
+	return 1, 1
+}
+
+// Counts the images loaded from each file and how many of them are still loaded
+type countingLoader struct {
+	loads  map[string]int
+	loaded int
+}
+
+func newCountingLoader() *countingLoader {
+	return &countingLoader{loads: make(map[string]int)}
+}
+
+func (l *countingLoader) LoadImage(file string) (ui.Image, error) {
+	if file == "missing.png" {
+		return nil, errors.New("not found")
+	}
+	l.loads[file]++
+	l.loaded++
+	return &fakeImage{file: file}, nil
+}
+
+func (l *countingLoader) CreateImage(img image.Image) (ui.Image, error) {
+	l.loaded++
+	return &fakeImage{}, nil
+}
+
+func (l *countingLoader) CreateSubImage(parent ui.Image, region image.Rectangle) (ui.Image, error) {
+	return parent, nil
+}
+
+func (l *countingLoader) UnloadImage(image ui.Image) {
+	l.loaded--
+}
+
+// A countingLoader that replaces the missing images with a shared placeholder
+type replacingLoader struct {
+	*countingLoader
+	placeholder ui.Image
+}
+
+func (l *replacingLoader) ReplaceMissingImage(file string, err error) (ui.Image, error) {
+	return l.placeholder, nil
+}
+
+func writePng(t *testing.T, file string) {
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Uploads until every file is done
+func finish(p *Preload, imageLoader ui.ImageLoader) {
+	for !p.Finished() {
+		p.Upload(imageLoader)
+	}
+}
+
+func TestPreload(t *testing.T) {
+	dir := t.TempDir()
+	a, b, missing := filepath.Join(dir, "a.png"), filepath.Join(dir, "b.png"), filepath.Join(dir, "missing.png")
+	writePng(t, a)
+	writePng(t, b)
+
+	tests := []struct {
+		name    string
+		replace bool
+	}{
+		{"missing file left out", false},
+		{"missing file replaced", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var imageLoader ui.ImageLoader = newCountingLoader()
+			if test.replace {
+				imageLoader = &replacingLoader{countingLoader: newCountingLoader(), placeholder: &fakeImage{}}
+			}
+
+			p := StartPreload([]string{a, b, a, missing}, 2)
+			if _, total := p.Progress(); total != 3 {
+				t.Fatalf("total = %d, want 3 unique files", total)
+			}
+			finish(p, imageLoader)
+
+			manager := NewManager(imageLoader)
+			p.Store(manager)
+			for _, file := range []string{a, b, missing} {
+				want := file != missing || test.replace
+				if manager.Contains(file) != want {
+					t.Errorf("%s cached = %v, want %v", filepath.Base(file), manager.Contains(file), want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreloadRelease(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.png")
+	writePng(t, file)
+
+	loader := newCountingLoader()
+	p := StartPreload([]string{file}, 0)
+	finish(p, loader)
+
+	p.Release(loader)
+	if loader.loaded != 0 {
+		t.Errorf("%d images are still loaded", loader.loaded)
+	}
+}
@@ -0,0 +1,241 @@
+package atlas
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+// Builder packs many small images into a few large textures (pages), so the sprites drawn from them don't break the
+// batches of the renderer by changing textures.
+type Builder struct {
+	//Maximum width and height of each page
+	PageSize int
+	//Transparent pixels between adjacent images
+	Padding int
+	//Pixels of the edges of each image repeated around it, so the filtering doesn't mix them with the transparent
+	//padding (or with other images) when the sprites are scaled or drawn at fractional positions
+	Extrusion int
+
+	entries []entry
+}
+
+type entry struct {
+	name  string
+	image image.Image
+}
+
+// Atlas contains the pages built by a Builder and the images that reference regions of them.
+type Atlas struct {
+	pages  []ui.Image
+	images map[string]ui.Image
+}
+
+func NewBuilder() *Builder {
+	return &Builder{PageSize: 2048, Padding: 1, Extrusion: 1}
+}
+
+// Add queues an already decoded image to be packed with the given name.
+func (b *Builder) Add(name string, img image.Image) {
+	b.entries = append(b.entries, entry{name: name, image: img})
+}
+
+// AddFile decodes an image file to be packed, using the file as its name.
+func (b *Builder) AddFile(file string) error {
+	img, err := ui.DecodeImage(file)
+	if err != nil {
+		return err
+	}
+
+	b.Add(file, img)
+	return nil
+}
+
+// Build packs the queued images and creates the pages with the loader. The returned images are drawn like any other,
+// and stay valid until the atlas is unloaded. The page size must be positive, and the padding and the extrusion can't be
+// negative.
+func (b *Builder) Build(imageLoader ui.ImageLoader) (*Atlas, error) {
+	if b.PageSize <= 0 || b.Padding < 0 || b.Extrusion < 0 {
+		return nil, fmt.Errorf("invalid atlas builder with page size %d, padding %d and extrusion %d", b.PageSize,
+			b.Padding, b.Extrusion)
+	}
+
+	pages, placements, err := b.pack()
+	if err != nil {
+		return nil, err
+	}
+
+	atlas := &Atlas{images: make(map[string]ui.Image, len(placements))}
+	for _, page := range pages {
+		pageImage, err := imageLoader.CreateImage(page)
+		if err != nil {
+			atlas.Unload(imageLoader)
+			return nil, fmt.Errorf("failed to create atlas page: %w", err)
+		}
+		atlas.pages = append(atlas.pages, pageImage)
+	}
+
+	for name, p := range placements {
+		img, err := imageLoader.CreateSubImage(atlas.pages[p.page], p.region)
+		if err != nil {
+			atlas.Unload(imageLoader)
+			return nil, fmt.Errorf("failed to create atlas image %q: %w", name, err)
+		}
+		atlas.images[name] = img
+	}
+
+	return atlas, nil
+}
+
+// Image returns the image added to the builder with the given name.
+func (a *Atlas) Image(name string) (ui.Image, bool) {
+	img, found := a.images[name]
+	return img, found
+}
+
+// Pages returns how many textures were needed to fit all the images.
+func (a *Atlas) Pages() int {
+	return len(a.pages)
+}
+
+// Unload releases the pages, invalidating every image of the atlas.
+func (a *Atlas) Unload(imageLoader ui.ImageLoader) {
+	for _, page := range a.pages {
+		imageLoader.UnloadImage(page)
+	}
+	a.pages = nil
+	a.images = nil
+}
+
+// Where an image was packed
+type placement struct {
+	page   int
+	region image.Rectangle
+}
+
+// Row of images in a page, as tall as its tallest image
+type shelf struct {
+	y      int
+	height int
+	//Where the next image of the shelf goes
+	x int
+}
+
+type page struct {
+	shelves []shelf
+	//Where the next shelf goes
+	nextY int
+	//Area used by the images, to crop the page
+	used image.Rectangle
+}
+
+// Places the images in shelves, from the tallest to the shortest, putting each one in the first shelf (of any page)
+// where it fits. Returns the pixels of each page and where each image is in them.
+func (b *Builder) pack() ([]*image.RGBA, map[string]placement, error) {
+	sorted := make([]entry, len(b.entries))
+	copy(sorted, b.entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].image.Bounds().Dy() > sorted[j].image.Bounds().Dy()
+	})
+
+	var pages []*page
+	placements := make(map[string]placement, len(sorted))
+	slots := make(map[string]image.Rectangle, len(sorted))
+
+	for _, e := range sorted {
+		if _, duplicate := placements[e.name]; duplicate {
+			return nil, nil, fmt.Errorf("duplicate atlas image %q", e.name)
+		}
+
+		//The slot includes the extruded edges; the padding is only needed between slots
+		size := e.image.Bounds().Size()
+		slotWidth, slotHeight := size.X+2*b.Extrusion, size.Y+2*b.Extrusion
+		if size.X <= 0 || size.Y <= 0 {
+			return nil, nil, fmt.Errorf("atlas image %q is empty", e.name)
+		}
+		if slotWidth > b.PageSize || slotHeight > b.PageSize {
+			return nil, nil, fmt.Errorf("atlas image %q (%dx%d) doesn't fit in a page of %dx%d", e.name, size.X, size.Y,
+				b.PageSize, b.PageSize)
+		}
+
+		index, slot := b.place(&pages, slotWidth, slotHeight)
+		pages[index].used = pages[index].used.Union(slot)
+
+		region := slot.Inset(b.Extrusion)
+		placements[e.name] = placement{page: index, region: region}
+		slots[e.name] = slot
+	}
+
+	images := make([]*image.RGBA, len(pages))
+	for i, p := range pages {
+		images[i] = image.NewRGBA(image.Rect(0, 0, p.used.Max.X, p.used.Max.Y))
+	}
+	for _, e := range sorted {
+		p := placements[e.name]
+		extrude(images[p.page], slots[e.name], e.image)
+	}
+
+	return images, placements, nil
+}
+
+// Finds room for a slot, creating a shelf or a page if needed, and returns the page and the slot's area in it
+func (b *Builder) place(pages *[]*page, width int, height int) (int, image.Rectangle) {
+	for index, p := range *pages {
+		for i := range p.shelves {
+			s := &p.shelves[i]
+			if height <= s.height && s.x+width <= b.PageSize {
+				slot := image.Rect(s.x, s.y, s.x+width, s.y+height)
+				s.x += width + b.Padding
+				return index, slot
+			}
+		}
+
+		if p.nextY+height <= b.PageSize {
+			return index, b.addShelf(p, width, height)
+		}
+	}
+
+	p := &page{}
+	*pages = append(*pages, p)
+	return len(*pages) - 1, b.addShelf(p, width, height)
+}
+
+func (b *Builder) addShelf(p *page, width int, height int) image.Rectangle {
+	s := shelf{y: p.nextY, height: height, x: width + b.Padding}
+	p.shelves = append(p.shelves, s)
+	p.nextY += height + b.Padding
+	return image.Rect(0, s.y, width, s.y+height)
+}
+
+// Draws the image in the middle of the slot, repeating its edge pixels until the slot's borders
+func extrude(target *image.RGBA, slot image.Rectangle, img image.Image) {
+	bounds := img.Bounds()
+	extrusion := (slot.Dx() - bounds.Dx()) / 2
+
+	region := slot.Inset(extrusion)
+	draw.Draw(target, region, img, bounds.Min, draw.Src)
+	if extrusion == 0 {
+		return
+	}
+
+	for y := slot.Min.Y; y < slot.Max.Y; y++ {
+		for x := slot.Min.X; x < slot.Max.X; x++ {
+			if (image.Point{X: x, Y: y}).In(region) {
+				continue
+			}
+			nearest := image.Point{X: clamp(x, region.Min.X, region.Max.X-1), Y: clamp(y, region.Min.Y, region.Max.Y-1)}
+			target.SetRGBA(x, y, target.RGBAAt(nearest.X, nearest.Y))
+		}
+	}
+}
+
+func clamp(value int, min int, max int) int {
+	if value < min {
+		return min
+	} else if value > max {
+		return max
+	}
+	return value
+}
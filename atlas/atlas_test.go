@@ -0,0 +1,196 @@
+package atlas
+
+import (
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(width int, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func newImageLoader(t *testing.T) ui.ImageLoader {
+	window, err := headless.NewWindowManager().CreateMainWindow(settings.DefaultSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return window.CreateImageLoader()
+}
+
+func TestPack(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder Builder
+		sizes   []image.Point
+		pages   int
+	}{
+		{
+			name:    "single shelf",
+			builder: Builder{PageSize: 64, Padding: 1, Extrusion: 1},
+			sizes:   []image.Point{{10, 10}, {10, 10}, {10, 10}},
+			pages:   1,
+		},
+		{
+			name:    "several shelves",
+			builder: Builder{PageSize: 32, Padding: 0, Extrusion: 0},
+			sizes:   []image.Point{{16, 16}, {16, 16}, {16, 8}, {16, 8}, {16, 8}},
+			pages:   1,
+		},
+		{
+			//With their extruded borders, the large images leave no room for anything else in their pages
+			name:    "several pages",
+			builder: Builder{PageSize: 16, Padding: 2, Extrusion: 1},
+			sizes:   []image.Point{{12, 12}, {12, 12}, {4, 4}},
+			pages:   3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := test.builder
+			names := make([]string, len(test.sizes))
+			for i, size := range test.sizes {
+				names[i] = string(rune('a' + i))
+				b.Add(names[i], solid(size.X, size.Y, color.RGBA{R: uint8(i + 1), A: 255}))
+			}
+
+			pages, placements, err := b.pack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pages) != test.pages {
+				t.Errorf("got %d pages, want %d", len(pages), test.pages)
+			}
+
+			for i, name := range names {
+				p := placements[name]
+				if p.region.Size() != test.sizes[i] {
+					t.Errorf("%s has size %v, want %v", name, p.region.Size(), test.sizes[i])
+				}
+				if !p.region.In(pages[p.page].Rect) {
+					t.Errorf("%s at %v is outside of its page %v", name, p.region, pages[p.page].Rect)
+				}
+
+				//The padding is kept between the extruded borders of any two images in the same page
+				slot := p.region.Inset(-b.Extrusion)
+				for _, other := range names[i+1:] {
+					o := placements[other]
+					if o.page == p.page && slot.Inset(-b.Padding).Overlaps(o.region.Inset(-b.Extrusion)) {
+						t.Errorf("%s at %v is too close to %s at %v", name, p.region, other, o.region)
+					}
+				}
+
+				//Every pixel of the image and of its extruded border has the image's color
+				want := color.RGBA{R: uint8(i + 1), A: 255}
+				for y := slot.Min.Y; y < slot.Max.Y; y++ {
+					for x := slot.Min.X; x < slot.Max.X; x++ {
+						if got := pages[p.page].RGBAAt(x, y); got != want {
+							t.Fatalf("%s has %v at (%d, %d), want %v", name, got, x, y, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExtrudeRepeatsEdges(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	colors := [2][2]color.RGBA{
+		{{R: 255, A: 255}, {G: 255, A: 255}},
+		{{B: 255, A: 255}, {R: 255, G: 255, A: 255}},
+	}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.SetRGBA(x, y, colors[y][x])
+		}
+	}
+
+	target := image.NewRGBA(image.Rect(0, 0, 6, 6))
+	extrude(target, image.Rect(0, 0, 6, 6), img)
+
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			//Each quadrant repeats the nearest pixel of the image
+			want := colors[y/3][x/3]
+			if got := target.RGBAAt(x, y); got != want {
+				t.Errorf("(%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	imageLoader := newImageLoader(t)
+
+	b := NewBuilder()
+	b.Add("wide", solid(20, 10, color.RGBA{R: 255, A: 255}))
+	b.Add("tall", solid(10, 20, color.RGBA{G: 255, A: 255}))
+
+	atlas, err := b.Build(imageLoader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer atlas.Unload(imageLoader)
+
+	if atlas.Pages() != 1 {
+		t.Errorf("got %d pages, want 1", atlas.Pages())
+	}
+	for name, size := range map[string]image.Point{"wide": {20, 10}, "tall": {10, 20}} {
+		img, found := atlas.Image(name)
+		if !found {
+			t.Fatalf("image %q not found", name)
+		}
+		if width, height := img.Size(); width != size.X || height != size.Y {
+			t.Errorf("%s is %dx%d, want %v", name, width, height, size)
+		}
+	}
+	if _, found := atlas.Image("missing"); found {
+		t.Error("found an image that wasn't added")
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	imageLoader := newImageLoader(t)
+
+	tests := []struct {
+		name    string
+		builder Builder
+		image   image.Image
+	}{
+		{"no page size", Builder{PageSize: 0}, solid(1, 1, color.RGBA{})},
+		{"negative padding", Builder{PageSize: 16, Padding: -1}, solid(1, 1, color.RGBA{})},
+		{"negative extrusion", Builder{PageSize: 16, Extrusion: -1}, solid(1, 1, color.RGBA{})},
+		{"image larger than a page", Builder{PageSize: 16, Extrusion: 1}, solid(15, 15, color.RGBA{})},
+		{"empty image", Builder{PageSize: 16}, solid(0, 0, color.RGBA{})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := test.builder
+			b.Add("a", test.image)
+			if _, err := b.Build(imageLoader); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		b := NewBuilder()
+		b.Add("a", solid(1, 1, color.RGBA{}))
+		b.Add("a", solid(2, 2, color.RGBA{}))
+		if _, err := b.Build(imageLoader); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
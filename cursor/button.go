@@ -0,0 +1,32 @@
+package cursor
+
+import "github.com/Hikarikun92/go-game-engine/key"
+
+type Button byte
+
+const (
+	LEFT     Button = 0
+	RIGHT    Button = 1
+	MIDDLE   Button = 2
+	BUTTON_4 Button = 3
+	BUTTON_5 Button = 4
+	BUTTON_6 Button = 5
+	BUTTON_7 Button = 6
+	BUTTON_8 Button = 7
+)
+
+type ButtonListener interface {
+	ButtonPressed(button Button, x int, y int, modifiers key.Modifier)
+	ButtonReleased(button Button, x int, y int, modifiers key.Modifier)
+}
+
+// DoubleClickListener can be implemented by states to be notified of double clicks, in addition to the presses that
+// make them.
+type DoubleClickListener interface {
+	DoubleClicked(button Button, x int, y int, modifiers key.Modifier)
+}
+
+// ScrollListener receives the offsets of mouse wheels and touchpads. Positive values scroll up and to the right.
+type ScrollListener interface {
+	Scrolled(xOffset float64, yOffset float64)
+}
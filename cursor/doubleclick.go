@@ -0,0 +1,45 @@
+package cursor
+
+import "time"
+
+// Maximum distance (in pixels, on each axis) between the two clicks of a double click
+const doubleClickDistance = 4
+
+// DoubleClickDetector recognizes two presses of the same button that happen close enough in time and position.
+type DoubleClickDetector struct {
+	Interval time.Duration
+
+	pending bool
+	button  Button
+	x       int
+	y       int
+	time    time.Time
+}
+
+func NewDoubleClickDetector(interval time.Duration) *DoubleClickDetector {
+	return &DoubleClickDetector{Interval: interval}
+}
+
+// Press registers a button press, returning whether it completes a double click. A third click starts a new double
+// click instead of completing another one.
+func (d *DoubleClickDetector) Press(button Button, x int, y int, at time.Time) bool {
+	if d.pending && button == d.button && at.Sub(d.time) <= d.Interval &&
+		abs(x-d.x) <= doubleClickDistance && abs(y-d.y) <= doubleClickDistance {
+		d.pending = false
+		return true
+	}
+
+	d.pending = true
+	d.button = button
+	d.x = x
+	d.y = y
+	d.time = at
+	return false
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDoubleClickDetector(t *testing.T) {
+	const interval = 500 * time.Millisecond
+
+	tests := []struct {
+		name   string
+		button Button
+		x, y   int
+		after  time.Duration
+		want   bool
+	}{
+		{"same place in time", LEFT, 100, 100, 200 * time.Millisecond, true},
+		{"at the end of the interval", LEFT, 100, 100, interval, true},
+		{"inside the distance", LEFT, 100 + doubleClickDistance, 100 - doubleClickDistance, 0, true},
+		{"past the interval", LEFT, 100, 100, interval + time.Millisecond, false},
+		{"past the distance horizontally", LEFT, 101 + doubleClickDistance, 100, 0, false},
+		{"past the distance vertically", LEFT, 100, 99 - doubleClickDistance, 0, false},
+		{"different button", RIGHT, 100, 100, 0, false},
+	}
+
+	start := time.Now()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := NewDoubleClickDetector(interval)
+			if d.Press(LEFT, 100, 100, start) {
+				t.Fatal("the first click completed a double click")
+			}
+			if got := d.Press(test.button, test.x, test.y, start.Add(test.after)); got != test.want {
+				t.Errorf("second click completed a double click = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDoubleClickDetectorThirdClick(t *testing.T) {
+	d := NewDoubleClickDetector(500 * time.Millisecond)
+	start := time.Now()
+
+	clicks := []bool{false, true, false, true}
+	for i, want := range clicks {
+		if got := d.Press(LEFT, 0, 0, start.Add(time.Duration(i)*100*time.Millisecond)); got != want {
+			t.Errorf("click %d completed a double click = %v, want %v", i+1, got, want)
+		}
+	}
+
+	//A click that isn't part of a double click starts a new one
+	d.Press(LEFT, 0, 0, start)
+	d.Press(RIGHT, 0, 0, start)
+	if !d.Press(RIGHT, 0, 0, start) {
+		t.Error("the click of another button didn't start a new double click")
+	}
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"testing"
+	"time"
+)
+
+// Simulates window events on its first update, recording the ones it receives, and ends the game on the next one
+type windowEventState struct {
+	recordingState
+	simulate func()
+	events   []string
+	//Whether the window may be closed
+	closable bool
+}
+
+func (s *windowEventState) Update(delta time.Duration) state.State {
+	if s.simulate == nil {
+		return nil
+	}
+	s.simulate()
+	s.simulate = nil
+	return s
+}
+
+func (s *windowEventState) WindowResized(width int, height int) {
+	s.events = append(s.events, fmt.Sprintf("resized %dx%d", width, height))
+}
+
+func (s *windowEventState) FocusGained() {
+	s.events = append(s.events, "focus gained")
+}
+
+func (s *windowEventState) FocusLost() {
+	s.events = append(s.events, "focus lost")
+}
+
+func (s *windowEventState) Minimized() {
+	s.events = append(s.events, "minimized")
+}
+
+func (s *windowEventState) Restored() {
+	s.events = append(s.events, "restored")
+}
+
+func (s *windowEventState) FilesDropped(paths []string) {
+	s.events = append(s.events, fmt.Sprint("dropped ", paths))
+}
+
+func (s *windowEventState) CloseRequested() bool {
+	s.events = append(s.events, "close requested")
+	return s.closable
+}
+
+func TestWindowEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		simulate func(window *headless.Window)
+		closable bool
+		events   []string
+		closed   bool
+	}{
+		{"resize", func(w *headless.Window) { w.Resize(1024, 768) }, false, []string{"resized 1024x768"}, false},
+		{"focus", func(w *headless.Window) {
+			w.Focus(false)
+			w.Focus(true)
+		}, false, []string{"focus lost", "focus gained"}, false},
+		{"minimize", func(w *headless.Window) {
+			w.Minimize(true)
+			w.Minimize(false)
+		}, false, []string{"minimized", "restored"}, false},
+		{"drop files", func(w *headless.Window) { w.DropFiles("a.png", "b.png") }, false,
+			[]string{"dropped [a.png b.png]"}, false},
+		{"close vetoed", func(w *headless.Window) { w.RequestClose() }, false, []string{"close requested"}, false},
+		{"close allowed", func(w *headless.Window) { w.RequestClose() }, true, []string{"close requested"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			windowManager := headless.NewWindowManager()
+			s := &windowEventState{closable: test.closable}
+			s.simulate = func() { test.simulate(windowManager.Window()) }
+
+			gameSettings := settings.DefaultSettings()
+			gameSettings.FramePacing = settings.UNCAPPED
+			if err := NewGame(windowManager, s, gameSettings).Run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(s.events) != fmt.Sprint(test.events) {
+				t.Errorf("got events %v, want %v", s.events, test.events)
+			}
+			//A closed window ends the game before the state ends it, so the simulation is the last update
+			if closed := windowManager.Window().ShouldClose(); closed != test.closed {
+				t.Errorf("window closed = %v, want %v", closed, test.closed)
+			}
+		})
+	}
+}
+
+func TestPauseOnFocusLoss(t *testing.T) {
+	tests := []struct {
+		pause   bool
+		updates int
+	}{
+		{false, 2},
+		{true, 1},
+	}
+
+	for _, test := range tests {
+		s := &recordingState{}
+		game := newTestGame(t, s, func(s *settings.Settings) { s.PauseOnFocusLoss = test.pause })
+
+		game.update(10 * time.Millisecond)
+		game.FocusLost()
+		game.update(10 * time.Millisecond)
+		game.FocusGained()
+		game.update(10 * time.Millisecond)
+
+		if len(s.updates) != test.updates+1 {
+			t.Errorf("pause on focus loss = %v: %d updates, want %d", test.pause, len(s.updates), test.updates+1)
+		}
+	}
+}
@@ -1,95 +1,481 @@
 package game
 
 import (
+	"context"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/asset"
 	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/input"
 	"github.com/Hikarikun92/go-game-engine/key"
 	"github.com/Hikarikun92/go-game-engine/settings"
 	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/stats"
 	"github.com/Hikarikun92/go-game-engine/ui"
+	"log"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
 type Game interface {
+	// Start runs the game until it ends or the process receives an interrupt or termination signal, logging the error
+	// that made it stop, if any.
 	Start()
+	// Run runs the game until it ends or the context is cancelled. The states are always unloaded and the window is
+	// always destroyed before returning. Returns nil if the game ended normally or was cancelled.
+	Run(ctx context.Context) error
+
+	// Stats returns the measurements of the last frames. It can be called from any goroutine.
+	Stats() stats.Stats
+	// SetStatsOverlay shows or hides a graph of the frame times over the game. It can be called from any goroutine.
+	SetStatsOverlay(visible bool)
+	// SetWindowMode switches between windowed and fullscreen. It can be called from any goroutine; the change happens
+	// before the next frame.
+	SetWindowMode(mode settings.WindowMode)
 }
 
 type gameImpl struct {
 	windowManager ui.WindowManager
 	settings      *settings.Settings
-	state         state.State
+	states        []*loadedState //From bottom to top
+	initialState  state.State
+
+	//Loader created by the window, used directly only for images that aren't owned by any state
+	imageLoader ui.ImageLoader
+	//Images shared by the states, each one loading them through its own ImageLoader
+	assets *asset.Manager
+
+	//Simulated time not yet consumed by fixed updates
+	accumulator time.Duration
+	//Transition between the current state and the previous one, if any
+	transition *activeTransition
+	//Assets being preloaded for the next state, if any
+	loading *activeLoading
+	//Whether the states are frozen because the window lost the focus
+	paused bool
+	//Whether the state returned by a LoadErrorHandler is being applied
+	handlingLoadError bool
+
+	stats        *stats.Collector
+	statsOverlay atomic.Bool
+	//Window mode requested by SetWindowMode and not yet applied, if any
+	windowMode atomic.Pointer[settings.WindowMode]
+
+	doubleClick *cursor.DoubleClickDetector
+	gamepads    *gamepad.Manager
+	input       *input.Snapshot
 }
 
 func NewGame(windowManager ui.WindowManager, initialState state.State, settings *settings.Settings) Game {
-	return &gameImpl{windowManager: windowManager, state: initialState, settings: settings}
+	game := &gameImpl{
+		windowManager: windowManager,
+		initialState:  initialState,
+		settings:      settings,
+		stats:         stats.NewCollector(),
+		doubleClick:   cursor.NewDoubleClickDetector(settings.DoubleClickInterval),
+		input:         input.NewSnapshot(),
+	}
+	game.statsOverlay.Store(settings.StatsOverlay)
+	return game
 }
 
 func (game *gameImpl) Start() {
-	window := game.windowManager.CreateMainWindow(game.settings)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := game.Run(ctx); err != nil {
+		log.Println(err)
+	}
+}
+
+func (game *gameImpl) Run(ctx context.Context) error {
+	if err := game.settings.Validate(); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+
+	window, err := game.windowManager.CreateMainWindow(game.settings)
+	if err != nil {
+		return fmt.Errorf("failed to create window: %w", err)
+	}
 	defer window.Destroy()
 
 	window.SetKeyListener(game)
+	window.SetTextListener(game)
 	window.SetCursorListener(game)
+	window.SetButtonListener(game)
+	window.SetScrollListener(game)
+	window.SetResizeListener(game)
+	window.SetFocusListener(game)
+	window.SetMinimizeListener(game)
+	window.SetCloseListener(game)
+	window.SetDropListener(game)
+	game.gamepads = gamepad.NewManager(window.CreateGamepadPoller(), game.settings.GamepadDeadZone)
 
-	imageLoader := window.CreateImageLoader()
+	game.imageLoader = window.CreateImageLoader()
+	if game.settings.MissingImageFallback {
+		game.imageLoader = ui.NewFallbackImageLoader(game.imageLoader, func(file string, err error) {
+			log.Println("Using placeholder for missing image:", err)
+		})
+	}
+	game.assets = asset.NewManager(game.imageLoader)
+
+	initial, err := game.load(game.initialState)
+	if err != nil {
+		return fmt.Errorf("failed to load initial state: %w", err)
+	}
+	game.states = []*loadedState{initial}
 
 	running := true
-	game.state.Load(imageLoader)
 
+	pacer := newFramePacer(game.settings)
+	budget := pacer.budget(game.settings)
 	previousTime := time.Now()
-	ticker := time.NewTicker(1 * time.Second / time.Duration(game.settings.Fps))
 
 	for running {
+		if mode := game.windowMode.Swap(nil); mode != nil {
+			window.SetWindowMode(*mode)
+		}
+
 		if window.ShouldClose() {
-			ticker.Stop()
-			game.state.Unload(imageLoader)
-			running = false
-			break
+			game.unloadAll()
+			return nil
 		}
 
-		select {
-		case t := <-ticker.C:
-			delta := t.Sub(previousTime)
+		t, ok := pacer.wait(ctx)
+		if !ok {
+			game.unloadAll()
+			return nil
+		}
 
-			nextState := game.state.Update(delta)
+		var frame stats.Frame
+		delta := t.Sub(previousTime)
+		frame.Start = t
+		frame.DroppedTicks = stats.DroppedTicks(delta, budget)
 
-			graphics := window.CreateGraphics()
-			game.state.Draw(graphics)
+		//Unlike the other input devices, gamepads don't have callbacks and must be polled
+		game.gamepads.Update(game)
+		game.input.SetGamepads(game.gamepads.Connected())
 
-			if nextState == nil {
-				ticker.Stop()
-				game.state.Unload(imageLoader)
-				running = false
-			} else if nextState != game.state {
-				game.state.Unload(imageLoader)
+		updateStart := time.Now()
+		nextState, alpha := game.update(delta)
+		frame.Update = time.Since(updateStart)
 
-				game.state = nextState
-				game.state.Load(imageLoader)
-			}
+		drawStart := time.Now()
+		graphics := window.CreateGraphics()
+		game.draw(graphics, alpha)
+		frame.Draw = time.Since(drawStart)
 
-			previousTime = t
+		counter, isCounter := graphics.(ui.DrawCallCounter)
+		if isCounter {
+			frame.DrawCalls = counter.DrawCalls()
+		}
+		batchCounter, isBatchCounter := graphics.(ui.BatchCounter)
+		if isBatchCounter {
+			frame.Batches = batchCounter.Batches()
 		}
 
+		//Drawn after the measurements so it doesn't affect them
+		if game.statsOverlay.Load() {
+			stats.DrawOverlay(graphics, game.stats.History(), budget, game.settings.Height)
+		}
+
+		running, err = game.apply(nextState)
+		if err != nil {
+			return err
+		}
+
+		previousTime = t
+
+		//With vsync, this is where the game waits for the monitor
+		swapStart := time.Now()
 		window.Update()
+		frame.Swap = time.Since(swapStart)
+
+		game.stats.Add(frame)
 	}
+
+	return nil
 }
 
-func (game *gameImpl) KeyPressed(k key.Key) {
-	listener, isListener := game.state.(key.Listener)
-	if isListener {
-		listener.KeyPressed(k)
+func (game *gameImpl) Stats() stats.Stats {
+	return game.stats.Stats()
+}
+
+func (game *gameImpl) SetStatsOverlay(visible bool) {
+	game.statsOverlay.Store(visible)
+}
+
+func (game *gameImpl) SetWindowMode(mode settings.WindowMode) {
+	game.windowMode.Store(&mode)
+}
+
+// Updates the current state, either once with the elapsed time or as many times as needed with a fixed step. Returns
+// the next state and the interpolation alpha to be used when drawing. The input snapshot advances before each update,
+// so every input edge is seen by exactly one of them; when no update runs, the events wait for the next one.
+func (game *gameImpl) update(delta time.Duration) (state.State, float64) {
+	if game.paused {
+		game.input.Advance() //Nothing is updated, so the input received meanwhile is dropped
+		return game.current(), 0
+	}
+	if game.transition != nil {
+		//The states are frozen during a transition
+		game.input.Advance()
+		game.transition.elapsed += delta
+		return game.current(), 0
+	}
+	if game.loading != nil {
+		//The states are also frozen while the next one is loading, but the loading screen can be animated
+		game.input.Advance()
+		if game.loading.screen != nil {
+			game.loading.screen.Update(delta)
+		}
+		return game.current(), 0
+	}
+
+	step := game.settings.FixedUpdateStep
+	if step <= 0 {
+		game.input.Advance()
+		game.updateBackground(delta)
+		return game.current().Update(delta), 0
+	}
+
+	//Clamp the elapsed time so a long frame doesn't trigger an ever-growing amount of updates
+	maxFrameTime := game.settings.MaxFrameTime
+	if maxFrameTime <= 0 {
+		maxFrameTime = settings.DefaultMaxFrameTime
+		if maxFrameTime < step {
+			maxFrameTime = step
+		}
+	}
+	if delta > maxFrameTime {
+		delta = maxFrameTime
+	}
+	game.accumulator += delta
+
+	for game.accumulator >= step {
+		game.accumulator -= step
+
+		game.input.Advance()
+		game.updateBackground(step)
+
+		current := game.current()
+		nextState := current.Update(step)
+		if nextState != current {
+			return nextState, 0 //Stop simulating a state that is being replaced
+		}
+	}
+
+	return game.current(), float64(game.accumulator) / float64(step)
+}
+
+// Draws every state in the stack, from bottom to top, so the current state appears over the others
+func (game *gameImpl) draw(graphics ui.Graphics, alpha float64) {
+	last := len(game.states) - 1
+	for _, s := range game.states[:last] {
+		game.drawState(s.state, graphics, alpha)
+	}
+
+	if game.transition != nil {
+		game.drawTransition(graphics, alpha)
+	} else {
+		game.drawState(game.states[last].state, graphics, alpha)
+	}
+
+	if game.loading != nil && game.loading.screen != nil {
+		game.drawState(game.loading.screen, graphics, alpha)
+	}
+}
+
+func (game *gameImpl) drawState(s state.State, graphics ui.Graphics, alpha float64) {
+	interpolated, isInterpolated := s.(state.Interpolated)
+	if isInterpolated && game.settings.FixedUpdateStep > 0 {
+		interpolated.DrawInterpolated(graphics, alpha)
+	} else {
+		s.Draw(graphics)
+	}
+}
+
+// The state that receives the input events, or nil while the states are frozen by a transition or a loading (so they
+// don't pile up input they can't react to)
+func (game *gameImpl) inputTarget() state.State {
+	if game.transition != nil || game.loading != nil {
+		return nil
+	}
+	return game.current()
+}
+
+func (game *gameImpl) KeyEvent(event key.Event) {
+	if event.Key != key.UNKNOWN && event.Key == game.settings.StatsOverlayKey {
+		if event.Action == key.PRESS {
+			game.statsOverlay.Store(!game.statsOverlay.Load())
+		}
+		return
+	}
+
+	game.input.KeyEvent(event)
+	current := game.inputTarget()
+
+	eventListener, isEventListener := current.(key.EventListener)
+	if isEventListener {
+		eventListener.KeyEvent(event)
+	}
+
+	//The simpler listener only knows about presses and releases of known keys
+	listener, isListener := current.(key.Listener)
+	if !isListener || event.Key == key.UNKNOWN {
+		return
+	}
+
+	if event.Action == key.PRESS {
+		listener.KeyPressed(event.Key)
+	} else if event.Action == key.RELEASE {
+		listener.KeyReleased(event.Key)
 	}
 }
 
-func (game *gameImpl) KeyReleased(k key.Key) {
-	listener, isListener := game.state.(key.Listener)
+func (game *gameImpl) CharacterTyped(char rune) {
+	listener, isListener := game.inputTarget().(key.TextListener)
 	if isListener {
-		listener.KeyReleased(k)
+		listener.CharacterTyped(char)
 	}
 }
 
 func (game *gameImpl) CursorMoved(x int, y int) {
-	listener, isListener := game.state.(cursor.Listener)
+	y = game.settings.Height - y //invert Y axis
+	game.input.CursorMoved(x, y)
+
+	listener, isListener := game.inputTarget().(cursor.Listener)
+	if isListener {
+		listener.CursorMoved(x, y)
+	}
+}
+
+func (game *gameImpl) ButtonPressed(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	y = game.settings.Height - y //invert Y axis
+	game.input.ButtonPressed(button, x, y, modifiers)
+	current := game.inputTarget()
+
+	listener, isListener := current.(cursor.ButtonListener)
+	if isListener {
+		listener.ButtonPressed(button, x, y, modifiers)
+	}
+
+	if game.doubleClick.Press(button, x, y, time.Now()) {
+		doubleClickListener, isDoubleClickListener := current.(cursor.DoubleClickListener)
+		if isDoubleClickListener {
+			doubleClickListener.DoubleClicked(button, x, y, modifiers)
+		}
+	}
+}
+
+func (game *gameImpl) ButtonReleased(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	y = game.settings.Height - y //invert Y axis
+	game.input.ButtonReleased(button, x, y, modifiers)
+
+	listener, isListener := game.inputTarget().(cursor.ButtonListener)
+	if isListener {
+		listener.ButtonReleased(button, x, y, modifiers)
+	}
+}
+
+func (game *gameImpl) Scrolled(xOffset float64, yOffset float64) {
+	game.input.Scrolled(xOffset, yOffset)
+
+	listener, isListener := game.inputTarget().(cursor.ScrollListener)
+	if isListener {
+		listener.Scrolled(xOffset, yOffset)
+	}
+}
+
+func (game *gameImpl) WindowResized(width int, height int) {
+	listener, isListener := game.current().(ui.ResizeListener)
+	if isListener {
+		listener.WindowResized(width, height)
+	}
+}
+
+func (game *gameImpl) FocusGained() {
+	game.paused = false
+
+	listener, isListener := game.current().(ui.FocusListener)
+	if isListener {
+		listener.FocusGained()
+	}
+}
+
+func (game *gameImpl) FocusLost() {
+	game.paused = game.settings.PauseOnFocusLoss
+
+	listener, isListener := game.current().(ui.FocusListener)
+	if isListener {
+		listener.FocusLost()
+	}
+}
+
+func (game *gameImpl) Minimized() {
+	listener, isListener := game.current().(ui.MinimizeListener)
+	if isListener {
+		listener.Minimized()
+	}
+}
+
+func (game *gameImpl) Restored() {
+	listener, isListener := game.current().(ui.MinimizeListener)
+	if isListener {
+		listener.Restored()
+	}
+}
+
+// CloseRequested lets the current state veto the closing of the window; the window closes if it doesn't care.
+func (game *gameImpl) CloseRequested() bool {
+	listener, isListener := game.current().(ui.CloseListener)
+	if isListener {
+		return listener.CloseRequested()
+	}
+	return true
+}
+
+func (game *gameImpl) FilesDropped(paths []string) {
+	listener, isListener := game.current().(ui.DropListener)
+	if isListener {
+		listener.FilesDropped(paths)
+	}
+}
+
+func (game *gameImpl) GamepadConnected(id gamepad.ID, name string) {
+	listener, isListener := game.current().(gamepad.ConnectionListener)
+	if isListener {
+		listener.GamepadConnected(id, name)
+	}
+}
+
+func (game *gameImpl) GamepadDisconnected(id gamepad.ID) {
+	listener, isListener := game.current().(gamepad.ConnectionListener)
+	if isListener {
+		listener.GamepadDisconnected(id)
+	}
+}
+
+func (game *gameImpl) GamepadButtonPressed(id gamepad.ID, button gamepad.Button) {
+	listener, isListener := game.inputTarget().(gamepad.Listener)
+	if isListener {
+		listener.GamepadButtonPressed(id, button)
+	}
+}
+
+func (game *gameImpl) GamepadButtonReleased(id gamepad.ID, button gamepad.Button) {
+	listener, isListener := game.inputTarget().(gamepad.Listener)
+	if isListener {
+		listener.GamepadButtonReleased(id, button)
+	}
+}
+
+func (game *gameImpl) GamepadAxisMoved(id gamepad.ID, axis gamepad.Axis, value float32) {
+	listener, isListener := game.inputTarget().(gamepad.Listener)
 	if isListener {
-		listener.CursorMoved(x, game.settings.Height-y) //invert Y axis
+		listener.GamepadAxisMoved(id, axis, value)
 	}
 }
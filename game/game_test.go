@@ -0,0 +1,167 @@
+package game
+
+import (
+	"github.com/Hikarikun92/go-game-engine/asset"
+	"github.com/Hikarikun92/go-game-engine/input"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"testing"
+	"time"
+)
+
+// Records the updates it receives and returns next from Update, or itself if next is nil
+type recordingState struct {
+	updates []time.Duration
+	next    state.State
+}
+
+func (s *recordingState) Load(imageLoader ui.ImageLoader) error {
+	return nil
+}
+
+func (s *recordingState) Update(delta time.Duration) state.State {
+	s.updates = append(s.updates, delta)
+	if s.next != nil {
+		return s.next
+	}
+	return s
+}
+
+func (s *recordingState) Draw(graphics ui.Graphics) {
+}
+
+func (s *recordingState) Unload(imageLoader ui.ImageLoader) {
+}
+
+// Creates a game running the given state, as Run would before the first frame, but without a window
+func newTestGame(t *testing.T, s state.State, change func(s *settings.Settings)) *gameImpl {
+	gameSettings := settings.DefaultSettings()
+	change(gameSettings)
+
+	window, err := headless.NewWindowManager().CreateMainWindow(gameSettings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := NewGame(nil, s, gameSettings).(*gameImpl)
+	game.imageLoader = window.CreateImageLoader()
+	game.assets = asset.NewManager(game.imageLoader)
+
+	loaded, err := game.load(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.states = []*loadedState{loaded}
+	return game
+}
+
+func TestFixedUpdateStep(t *testing.T) {
+	const step = 10 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		maxFrameTime time.Duration
+		deltas       []time.Duration
+		updates      int
+		alpha        float64
+	}{
+		{"shorter than a step", 0, []time.Duration{5 * time.Millisecond}, 0, 0.5},
+		{"accumulated over frames", 0, []time.Duration{5 * time.Millisecond, 5 * time.Millisecond}, 1, 0},
+		{"several steps in a frame", 0, []time.Duration{25 * time.Millisecond}, 2, 0.5},
+		{"clamped by the maximum frame time", 50 * time.Millisecond, []time.Duration{time.Second}, 5, 0},
+		{"clamped by default", 0, []time.Duration{time.Second}, int(settings.DefaultMaxFrameTime / step), 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &recordingState{}
+			game := newTestGame(t, s, func(s *settings.Settings) {
+				s.FixedUpdateStep = step
+				s.MaxFrameTime = test.maxFrameTime
+			})
+
+			var alpha float64
+			for _, delta := range test.deltas {
+				_, alpha = game.update(delta)
+			}
+
+			if len(s.updates) != test.updates {
+				t.Errorf("got %d updates, want %d", len(s.updates), test.updates)
+			}
+			for i, delta := range s.updates {
+				if delta != step {
+					t.Errorf("update %d received %v, want the step", i, delta)
+				}
+			}
+			if alpha != test.alpha {
+				t.Errorf("alpha = %v, want %v", alpha, test.alpha)
+			}
+		})
+	}
+}
+
+func TestVariableUpdateStep(t *testing.T) {
+	s := &recordingState{}
+	game := newTestGame(t, s, func(s *settings.Settings) { s.FixedUpdateStep = 0 })
+
+	_, alpha := game.update(16 * time.Millisecond)
+	if len(s.updates) != 1 || s.updates[0] != 16*time.Millisecond || alpha != 0 {
+		t.Errorf("got updates %v and alpha %v, want a single update with the whole delta", s.updates, alpha)
+	}
+}
+
+func TestFixedUpdateStepStopsWhenReplaced(t *testing.T) {
+	next := &recordingState{}
+	s := &recordingState{next: next}
+	game := newTestGame(t, s, func(s *settings.Settings) { s.FixedUpdateStep = 10 * time.Millisecond })
+
+	nextState, _ := game.update(30 * time.Millisecond)
+	if nextState != next || len(s.updates) != 1 {
+		t.Fatalf("the state was updated %d times after asking to be replaced", len(s.updates))
+	}
+
+	if _, err := game.apply(nextState); err != nil {
+		t.Fatal(err)
+	}
+	if game.current() != next || game.accumulator != 0 {
+		t.Error("the leftover time of the replaced state was kept")
+	}
+}
+
+// Counts the updates that see a press of the space key in the input snapshot
+type inputState struct {
+	recordingState
+	snapshot *input.Snapshot
+	presses  int
+}
+
+func (s *inputState) UseInput(snapshot *input.Snapshot) {
+	s.snapshot = snapshot
+}
+
+func (s *inputState) Update(delta time.Duration) state.State {
+	if s.snapshot.JustPressed(key.SPACE) {
+		s.presses++
+	}
+	s.updates = append(s.updates, delta)
+	return s
+}
+
+func TestInputEdgesSeenByOneUpdate(t *testing.T) {
+	s := &inputState{}
+	game := newTestGame(t, s, func(s *settings.Settings) { s.FixedUpdateStep = 10 * time.Millisecond })
+
+	game.KeyEvent(key.Event{Key: key.SPACE, Action: key.PRESS})
+	game.update(5 * time.Millisecond) //No update runs, so the press waits for the next one
+	if s.presses != 0 {
+		t.Fatalf("the press was seen %d times before any update", s.presses)
+	}
+
+	game.update(35 * time.Millisecond)
+	if len(s.updates) != 4 || s.presses != 1 {
+		t.Errorf("the press was seen by %d of %d updates, want 1", s.presses, len(s.updates))
+	}
+}
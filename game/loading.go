@@ -0,0 +1,101 @@
+package game
+
+import (
+	"github.com/Hikarikun92/go-game-engine/asset"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"log"
+)
+
+// The assets of a Preloader being decoded in the background. The value returned from Update that requested the state is
+// only applied once all of them are ready.
+type activeLoading struct {
+	request state.State
+	preload *asset.Preload
+	screen  state.LoadingScreen //May be nil
+	//The loaded screen, which owns its images
+	loadedScreen *loadedState
+}
+
+// Returns the state that will be loaded when applying the value returned from Update, or nil if there's none
+func loadTarget(nextState state.State, current state.State) state.State {
+	switch next := nextState.(type) {
+	case nil, *state.PopRequest:
+		return nil
+	case *state.PushRequest:
+		return next.Child
+	case *state.TransitionRequest:
+		return next.Next
+	default:
+		if next == current {
+			return nil
+		}
+		return next
+	}
+}
+
+// Starts decoding the assets of the next state if it is a Preloader. Returns false if there's nothing to preload.
+func (game *gameImpl) startLoading(nextState state.State) bool {
+	preloader, isPreloader := loadTarget(nextState, game.current()).(state.Preloader)
+	if !isPreloader {
+		return false
+	}
+
+	//Images already in use by other states don't need to be loaded again
+	var files []string
+	for _, file := range preloader.Assets() {
+		if !game.assets.Contains(file) {
+			files = append(files, file)
+		}
+	}
+	preload := asset.StartPreload(files, game.settings.PreloadWorkers)
+
+	screen := preloader.LoadingScreen()
+	var loadedScreen *loadedState
+	if screen != nil {
+		var err error
+		if loadedScreen, err = game.load(screen); err != nil {
+			log.Println("Failed to load loading screen:", err)
+			screen = nil
+		} else {
+			screen.Progress(preload.Progress())
+		}
+	}
+
+	game.loading = &activeLoading{request: nextState, preload: preload, screen: screen, loadedScreen: loadedScreen}
+	return true
+}
+
+// Uploads the images decoded since the last frame and, when all of them are ready, loads the next state. Returns like
+// apply.
+func (game *gameImpl) continueLoading() (bool, error) {
+	loading := game.loading
+
+	loading.preload.Upload(game.imageLoader)
+	if loading.screen != nil {
+		loading.screen.Progress(loading.preload.Progress())
+	}
+
+	if !loading.preload.Finished() {
+		return true, nil
+	}
+
+	if loading.screen != nil {
+		game.unload(loading.loadedScreen)
+	}
+	game.loading = nil
+
+	//The images are only kept if the next state takes them while loading
+	loading.preload.Store(game.assets)
+	running, err := game.change(loading.request)
+	game.assets.Prune()
+	return running, err
+}
+
+// Gives up on a loading in progress, releasing what was already loaded
+func (game *gameImpl) abortLoading() {
+	if game.loading.screen != nil {
+		game.unload(game.loading.loadedScreen)
+	}
+	game.loading.preload.Release(game.imageLoader)
+	game.loading = nil
+}
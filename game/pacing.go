@@ -0,0 +1,81 @@
+package game
+
+import (
+	"context"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"runtime"
+	"time"
+)
+
+// How long before a frame's deadline the pacer stops sleeping and starts yielding, since the operating system may wake
+// a sleeping goroutine a few milliseconds late
+const spinThreshold = 2 * time.Millisecond
+
+// Budget used by the statistics when the frame rate isn't known
+const defaultBudget = time.Second / 60
+
+// Waits between frames according to the frame pacing chosen in the settings
+type framePacer struct {
+	pacing settings.FramePacing
+	period time.Duration //Zero if the frames aren't capped
+	next   time.Time     //Deadline of the next frame when capped
+}
+
+func newFramePacer(s *settings.Settings) *framePacer {
+	pacer := &framePacer{pacing: s.FramePacing}
+	if s.FramePacing == settings.CAPPED {
+		pacer.period = time.Second / time.Duration(s.Fps)
+	}
+	return pacer
+}
+
+// Blocks until the next frame should start and returns its start time, or false if the context was cancelled first.
+// With vsync the waiting happens when swapping the buffers instead.
+func (p *framePacer) wait(ctx context.Context) (time.Time, bool) {
+	if p.period <= 0 {
+		select {
+		case <-ctx.Done():
+			return time.Time{}, false
+		default:
+			return time.Now(), true
+		}
+	}
+
+	if sleep := p.delay(time.Now()) - spinThreshold; sleep > 0 {
+		timer := time.NewTimer(sleep)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return time.Time{}, false
+		case <-timer.C:
+		}
+	}
+
+	for time.Now().Before(p.next) {
+		runtime.Gosched()
+	}
+
+	start := time.Now()
+	p.next = p.next.Add(p.period)
+	return start, ctx.Err() == nil
+}
+
+// Returns how long to wait from now until the deadline of the next frame
+func (p *framePacer) delay(now time.Time) time.Duration {
+	if p.next.IsZero() || now.Sub(p.next) > p.period {
+		//Too far behind (or the first frame): start counting again instead of rushing to catch up
+		p.next = now
+	}
+	return p.next.Sub(now)
+}
+
+// Returns the expected duration of a frame, used to detect dropped frames and as the budget of the statistics overlay
+func (p *framePacer) budget(s *settings.Settings) time.Duration {
+	if p.period > 0 {
+		return p.period
+	}
+	if s.Fps > 0 {
+		return time.Second / time.Duration(s.Fps)
+	}
+	return defaultBudget
+}
@@ -0,0 +1,95 @@
+package game
+
+import (
+	"context"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"testing"
+	"time"
+)
+
+func TestFramePacerDelay(t *testing.T) {
+	const ms = time.Millisecond
+	const period = 10 * ms
+	start := time.Now()
+
+	//The times are relative to the start
+	tests := []struct {
+		name  string
+		first bool
+		next  time.Duration
+		now   time.Duration
+		delay time.Duration
+		//Deadline of the frame after the call
+		deadline time.Duration
+	}{
+		{"first frame", true, 0, 0, 0, 0},
+		{"early", false, 10 * ms, 4 * ms, 6 * ms, 10 * ms},
+		{"on time", false, 10 * ms, 10 * ms, 0, 10 * ms},
+		{"a little late", false, 10 * ms, 15 * ms, -5 * ms, 10 * ms},
+		{"more than a period late", false, 10 * ms, 25 * ms, 0, 25 * ms},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := &framePacer{pacing: settings.CAPPED, period: period}
+			if !test.first {
+				p.next = start.Add(test.next)
+			}
+
+			if delay := p.delay(start.Add(test.now)); delay != test.delay {
+				t.Errorf("delay = %v, want %v", delay, test.delay)
+			}
+			if deadline := p.next.Sub(start); deadline != test.deadline {
+				t.Errorf("deadline = %v, want %v", deadline, test.deadline)
+			}
+		})
+	}
+}
+
+func TestFramePacerBudget(t *testing.T) {
+	tests := []struct {
+		name   string
+		pacing settings.FramePacing
+		fps    int
+		budget time.Duration
+	}{
+		{"capped", settings.CAPPED, 50, 20 * time.Millisecond},
+		{"vsync", settings.VSYNC, 50, 20 * time.Millisecond},
+		{"uncapped", settings.UNCAPPED, 100, 10 * time.Millisecond},
+		{"uncapped without a frame rate", settings.UNCAPPED, 0, defaultBudget},
+	}
+
+	for _, test := range tests {
+		s := settings.DefaultSettings()
+		s.FramePacing, s.Fps = test.pacing, test.fps
+
+		p := newFramePacer(s)
+		if capped := p.period > 0; capped != (test.pacing == settings.CAPPED) {
+			t.Errorf("%s: period = %v", test.name, p.period)
+		}
+		if budget := p.budget(s); budget != test.budget {
+			t.Errorf("%s: budget = %v, want %v", test.name, budget, test.budget)
+		}
+	}
+}
+
+// The headless window has no monitor, but vsync still limits the frames instead of running as fast as possible
+func TestHeadlessVsync(t *testing.T) {
+	gameSettings := settings.DefaultSettings()
+	gameSettings.FramePacing = settings.VSYNC
+	gameSettings.Fps = 100
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	windowManager := headless.NewWindowManager()
+	if err := NewGame(windowManager, &recordingState{}, gameSettings).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	//About 10 frames in 100ms at 100 Hz
+	if frames := windowManager.Window().Frames(); frames < 1 || frames > 15 {
+		t.Errorf("%d frames were presented in 100ms, want about 10", frames)
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"testing"
+	"time"
+)
+
+// Pushes a child on its first update, then runs an action (e.g. cancelling the game) when the child is updated
+type pushingState struct {
+	lifecycleState
+	child  *actionState
+	pushed bool
+}
+
+func (s *pushingState) Update(delta time.Duration) state.State {
+	if !s.pushed {
+		s.pushed = true
+		return state.Push(s.child)
+	}
+	return s
+}
+
+type actionState struct {
+	lifecycleState
+	action func()
+}
+
+func (s *actionState) Update(delta time.Duration) state.State {
+	s.action()
+	return s
+}
+
+func TestRunStops(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(cancel context.CancelFunc, window *headless.Window)
+	}{
+		{"context cancelled", func(cancel context.CancelFunc, window *headless.Window) { cancel() }},
+		{"window closed", func(cancel context.CancelFunc, window *headless.Window) { window.Close() }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var log []string
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			windowManager := headless.NewWindowManager()
+
+			child := &actionState{lifecycleState: lifecycleState{name: "child", log: &log}}
+			child.action = func() { test.stop(cancel, windowManager.Window()) }
+			root := &pushingState{lifecycleState: lifecycleState{name: "root", log: &log}, child: child}
+
+			gameSettings := settings.DefaultSettings()
+			gameSettings.FramePacing = settings.UNCAPPED
+			if err := NewGame(windowManager, root, gameSettings).Run(ctx); err != nil {
+				t.Fatalf("Run returned %v, want nil", err)
+			}
+
+			want := []string{"load root", "load child", "pause root", "unload child", "unload root"}
+			if fmt.Sprint(log) != fmt.Sprint(want) {
+				t.Errorf("log = %v, want %v", log, want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var log []string
+	tests := []struct {
+		name   string
+		change func(s *settings.Settings)
+		state  state.State
+	}{
+		{"invalid settings", func(s *settings.Settings) { s.Width = 0 }, &lifecycleState{name: "root", log: &log}},
+		{"initial state fails to load", func(s *settings.Settings) {},
+			&lifecycleState{name: "root", log: &log, loadErr: errors.New("boom")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameSettings := settings.DefaultSettings()
+			test.change(gameSettings)
+
+			game := NewGame(headless.NewWindowManager(), test.state, gameSettings)
+			if err := game.Run(context.Background()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
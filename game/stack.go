@@ -0,0 +1,157 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"time"
+)
+
+// A state that was loaded into the game. A new one is created every time a state is loaded, and it owns the images of
+// the state in the asset manager, so the states themselves don't need to be comparable (and two equal states don't
+// share the references of their images).
+type loadedState struct {
+	state state.State
+}
+
+// The state on top of the stack, which receives the input and decides what happens next
+func (game *gameImpl) current() state.State {
+	return game.states[len(game.states)-1].state
+}
+
+// Changes the stack according to what the current state returned from Update. Returns false when there's no state left
+// to run, along with the reason if the game can't go on because of an error.
+func (game *gameImpl) apply(nextState state.State) (bool, error) {
+	if game.transition != nil {
+		if nextState == game.current() {
+			game.finishTransition()
+			return true, nil
+		}
+		game.interruptTransition()
+	}
+	if game.loading != nil {
+		return game.continueLoading()
+	}
+	if game.startLoading(nextState) {
+		return true, nil
+	}
+
+	return game.change(nextState)
+}
+
+// Applies the value returned from Update right away, loading and unloading states as needed
+func (game *gameImpl) change(nextState state.State) (bool, error) {
+	current := game.current()
+
+	switch next := nextState.(type) {
+	case nil:
+		game.unloadAll()
+		return false, nil
+	case *state.PushRequest:
+		child, err := game.load(next.Child)
+		if err != nil {
+			return game.loadFailed(next.Child, err)
+		}
+
+		pausable, isPausable := current.(state.Pausable)
+		if isPausable {
+			pausable.Pause()
+		}
+		game.states = append(game.states, child)
+	case *state.PopRequest:
+		if len(game.states) == 1 {
+			game.unloadAll()
+			return false, nil
+		}
+
+		game.unload(game.states[len(game.states)-1])
+		game.states = game.states[:len(game.states)-1]
+
+		pausable, isPausable := game.current().(state.Pausable)
+		if isPausable {
+			pausable.Resume()
+		}
+	case *state.TransitionRequest:
+		loaded, err := game.load(next.Next)
+		if err != nil {
+			return game.loadFailed(next.Next, err)
+		}
+		game.startTransition(next.Transition, loaded)
+	default:
+		if next == current {
+			return true, nil
+		}
+
+		//The next state is loaded first so the current one is still available if it fails
+		loaded, err := game.load(next)
+		if err != nil {
+			return game.loadFailed(next, err)
+		}
+
+		game.unload(game.states[len(game.states)-1])
+		game.states[len(game.states)-1] = loaded
+	}
+
+	game.accumulator = 0
+	return true, nil
+}
+
+// Lets the current state decide what to do after the state it asked for failed to load, failing the game if it can't.
+// The game also fails if the state returned by the handler fails to load too, instead of asking the handler forever.
+func (game *gameImpl) loadFailed(failed state.State, err error) (bool, error) {
+	handler, isHandler := game.current().(state.LoadErrorHandler)
+	if !isHandler || game.handlingLoadError {
+		game.unloadAll()
+		return false, fmt.Errorf("failed to load state: %w", err)
+	}
+
+	game.handlingLoadError = true
+	defer func() { game.handlingLoadError = false }()
+	return game.apply(handler.LoadFailed(failed, err))
+}
+
+// Unloads every state in the stack, from top to bottom (including a state that is still transitioning out and the
+// assets being preloaded)
+func (game *gameImpl) unloadAll() {
+	if game.loading != nil {
+		game.abortLoading()
+	}
+	if game.transition != nil {
+		game.interruptTransition()
+	}
+
+	for i := len(game.states) - 1; i >= 0; i-- {
+		game.unload(game.states[i])
+	}
+}
+
+// Updates the states below the current one that asked to keep running in the background
+func (game *gameImpl) updateBackground(delta time.Duration) {
+	for _, s := range game.states[:len(game.states)-1] {
+		background, isBackground := s.state.(state.Background)
+		if isBackground {
+			background.BackgroundUpdate(delta)
+		}
+	}
+}
+
+// Loads a state with its own ImageLoader, which keeps track of the images it uses, giving it the input snapshot first
+// if it wants it
+func (game *gameImpl) load(s state.State) (*loadedState, error) {
+	inputUser, isInputUser := s.(state.InputUser)
+	if isInputUser {
+		inputUser.UseInput(game.input)
+	}
+
+	loaded := &loadedState{state: s}
+	if err := s.Load(game.assets.For(loaded)); err != nil {
+		game.assets.Release(loaded) //In case the state didn't release what it loaded before failing
+		return nil, err
+	}
+	return loaded, nil
+}
+
+// Unloads a state, also releasing any image it didn't unload itself
+func (game *gameImpl) unload(loaded *loadedState) {
+	loaded.state.Unload(game.assets.For(loaded))
+	game.assets.Release(loaded)
+}
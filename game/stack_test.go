@@ -0,0 +1,193 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Records its lifecycle in a log shared by the states of a test, and loads an image so the asset manager tracks it
+type lifecycleState struct {
+	name    string
+	log     *[]string
+	loadErr error
+	image   ui.Image
+}
+
+func (s *lifecycleState) Load(imageLoader ui.ImageLoader) error {
+	*s.log = append(*s.log, "load "+s.name)
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+
+	img, err := imageLoader.CreateImage(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	s.image = img
+	return err
+}
+
+func (s *lifecycleState) Update(delta time.Duration) state.State {
+	return s
+}
+
+func (s *lifecycleState) Draw(graphics ui.Graphics) {
+}
+
+func (s *lifecycleState) Unload(imageLoader ui.ImageLoader) {
+	*s.log = append(*s.log, "unload "+s.name)
+	imageLoader.UnloadImage(s.image)
+}
+
+func (s *lifecycleState) Pause() {
+	*s.log = append(*s.log, "pause "+s.name)
+}
+
+func (s *lifecycleState) Resume() {
+	*s.log = append(*s.log, "resume "+s.name)
+}
+
+func TestStack(t *testing.T) {
+	var log []string
+	newState := func(name string) *lifecycleState {
+		return &lifecycleState{name: name, log: &log}
+	}
+
+	first := newState("first")
+	game := newTestGame(t, first, func(s *settings.Settings) {})
+	second, third := newState("second"), newState("third")
+
+	steps := []struct {
+		next    state.State
+		running bool
+		log     []string
+		depth   int
+	}{
+		{first, true, nil, 1},
+		{state.Push(second), true, []string{"load second", "pause first"}, 2},
+		{third, true, []string{"load third", "unload second"}, 2},
+		{state.Pop(), true, []string{"unload third", "resume first"}, 1},
+		{state.Push(second), true, []string{"load second", "pause first"}, 2},
+		{nil, false, []string{"unload second", "unload first"}, 2},
+	}
+
+	log = nil
+	for i, step := range steps {
+		running, err := game.apply(step.next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if running != step.running || fmt.Sprint(log) != fmt.Sprint(step.log) || len(game.states) != step.depth {
+			t.Errorf("step %d: running %v with %d states and log %v, want %v with %d states and log %v", i,
+				running, len(game.states), log, step.running, step.depth, step.log)
+		}
+		log = nil
+	}
+}
+
+func TestPopLastState(t *testing.T) {
+	var log []string
+	game := newTestGame(t, &lifecycleState{name: "only", log: &log}, func(s *settings.Settings) {})
+
+	running, err := game.apply(state.Pop())
+	if running || err != nil {
+		t.Errorf("apply returned %v and %v, want the game to end normally", running, err)
+	}
+	if fmt.Sprint(log) != "[load only unload only]" {
+		t.Errorf("log = %v, want the state loaded and unloaded", log)
+	}
+}
+
+// Handles every load error by returning the fallback, or itself if there is none
+type fallbackState struct {
+	lifecycleState
+	fallback state.State
+	failures int
+}
+
+func (s *fallbackState) LoadFailed(failed state.State, err error) state.State {
+	s.failures++
+	if s.fallback == nil {
+		return s
+	}
+	return s.fallback
+}
+
+func TestLoadFailed(t *testing.T) {
+	var log []string
+	broken := func(name string) *lifecycleState {
+		return &lifecycleState{name: name, log: &log, loadErr: errors.New("boom")}
+	}
+
+	tests := []struct {
+		name     string
+		fallback state.State
+		running  bool
+		log      []string
+	}{
+		{"fallback loads", &lifecycleState{name: "fallback", log: &log}, true,
+			[]string{"load next", "load fallback", "unload current"}},
+		{"fallback fails too", broken("fallback"), false, []string{"load next", "load fallback", "unload current"}},
+		{"handler keeps the current state", nil, true, []string{"load next"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := &fallbackState{lifecycleState: lifecycleState{name: "current", log: &log},
+				fallback: test.fallback}
+			game := newTestGame(t, current, func(s *settings.Settings) {})
+			log = nil
+
+			running, err := game.apply(broken("next"))
+			if running != test.running || (err == nil) != test.running {
+				t.Errorf("apply returned %v and %v, want running = %v", running, err, test.running)
+			}
+			if err != nil && !strings.Contains(err.Error(), "boom") {
+				t.Errorf("error %q doesn't contain the cause", err)
+			}
+			if current.failures != 1 {
+				t.Errorf("the handler was called %d times, want 1", current.failures)
+			}
+			if fmt.Sprint(log) != fmt.Sprint(test.log) {
+				t.Errorf("log = %v, want %v", log, test.log)
+			}
+		})
+	}
+}
+
+func TestLoadFailedWithoutHandler(t *testing.T) {
+	var log []string
+	game := newTestGame(t, &lifecycleState{name: "current", log: &log}, func(s *settings.Settings) {})
+
+	broken := &lifecycleState{name: "child", log: &log, loadErr: errors.New("boom")}
+	if running, err := game.apply(state.Push(broken)); running || err == nil {
+		t.Errorf("apply returned %v and %v, want the game to end with an error", running, err)
+	}
+}
+
+// The same state can be pushed twice; each load owns its own references to the images
+func TestStackSameStateTwice(t *testing.T) {
+	var log []string
+	game := newTestGame(t, &lifecycleState{name: "first", log: &log}, func(s *settings.Settings) {})
+	child := &lifecycleState{name: "child", log: &log}
+
+	for i := 0; i < 2; i++ {
+		if _, err := game.apply(state.Push(child)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if game.states[1] == game.states[2] {
+		t.Fatal("both loads of the state share the same owner")
+	}
+
+	if _, err := game.apply(state.Pop()); err != nil {
+		t.Fatal(err)
+	}
+	if game.current() != child || len(game.states) != 2 {
+		t.Errorf("popping one copy of the state didn't keep the other")
+	}
+}
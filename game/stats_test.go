@@ -0,0 +1,106 @@
+package game
+
+import (
+	"context"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"testing"
+	"time"
+)
+
+// Takes too long to draw its first frame, then ends the game after a few more
+type slowState struct {
+	draws int
+	//Updates run between each pair of frames
+	updates []int
+}
+
+func (s *slowState) Load(imageLoader ui.ImageLoader) error {
+	return nil
+}
+
+func (s *slowState) Update(delta time.Duration) state.State {
+	s.updates[len(s.updates)-1]++
+	if s.draws >= 3 {
+		return nil
+	}
+	return s
+}
+
+func (s *slowState) Draw(graphics ui.Graphics) {
+	s.draws++
+	s.updates = append(s.updates, 0)
+	if s.draws == 1 {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (s *slowState) Unload(imageLoader ui.ImageLoader) {
+}
+
+// The dropped ticks count the whole time that was lost, even though the updates only catch up with part of it
+func TestDroppedTicksWithClampedAccumulator(t *testing.T) {
+	gameSettings := settings.DefaultSettings()
+	gameSettings.FramePacing = settings.UNCAPPED
+	gameSettings.FixedUpdateStep = 10 * time.Millisecond
+	gameSettings.MaxFrameTime = 20 * time.Millisecond
+
+	s := &slowState{updates: []int{0}}
+	game := NewGame(headless.NewWindowManager(), s, gameSettings)
+	if err := game.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	//The frame after the slow one ran at most the updates fitting in the maximum frame time
+	if s.updates[1] > 2 {
+		t.Errorf("%d updates ran after the slow frame, want at most 2", s.updates[1])
+	}
+	//About 100ms were lost with a budget of 1/60 of a second
+	if dropped := game.Stats().DroppedTicks; dropped < 4 {
+		t.Errorf("dropped ticks = %d, want at least 4", dropped)
+	}
+}
+
+// Records the keys it receives
+type keyState struct {
+	recordingState
+	keys []key.Key
+}
+
+func (s *keyState) KeyEvent(event key.Event) {
+	s.keys = append(s.keys, event.Key)
+}
+
+func TestStatsOverlayKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		overlayKey key.Key
+		event      key.Event
+		toggled    bool
+		delivered  bool
+	}{
+		{"press of the overlay key", key.F3, key.Event{Key: key.F3, Action: key.PRESS}, true, false},
+		{"release of the overlay key", key.F3, key.Event{Key: key.F3, Action: key.RELEASE}, false, false},
+		{"another key", key.F3, key.Event{Key: key.F1, Action: key.PRESS}, false, true},
+		{"unknown key without an overlay key", key.UNKNOWN, key.Event{Key: key.UNKNOWN, Action: key.PRESS}, false,
+			true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &keyState{}
+			game := newTestGame(t, s, func(s *settings.Settings) { s.StatsOverlayKey = test.overlayKey })
+
+			game.KeyEvent(test.event)
+			if game.statsOverlay.Load() != test.toggled {
+				t.Errorf("overlay visible = %v, want %v", game.statsOverlay.Load(), test.toggled)
+			}
+			if delivered := len(s.keys) == 1; delivered != test.delivered {
+				t.Errorf("delivered to the state = %v, want %v", delivered, test.delivered)
+			}
+		})
+	}
+}
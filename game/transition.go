@@ -0,0 +1,51 @@
+package game
+
+import (
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"time"
+)
+
+// A transition in progress. The incoming state is already on top of the stack, while the outgoing state is kept here
+// until the transition finishes.
+type activeTransition struct {
+	transition state.Transition
+	from       *loadedState
+	elapsed    time.Duration
+}
+
+func (t *activeTransition) progress() float64 {
+	duration := t.transition.Duration()
+	if duration <= 0 || t.elapsed >= duration {
+		return 1.0
+	}
+	return float64(t.elapsed) / float64(duration)
+}
+
+// Starts drawing the transition; both states are drawn during it, so the incoming one must already be loaded
+func (game *gameImpl) startTransition(transition state.Transition, next *loadedState) {
+	game.transition = &activeTransition{transition: transition, from: game.states[len(game.states)-1]}
+	game.states[len(game.states)-1] = next
+}
+
+// Unloads the outgoing state once the transition has run for its whole duration
+func (game *gameImpl) finishTransition() {
+	if game.transition.progress() < 1.0 {
+		return
+	}
+
+	game.interruptTransition()
+}
+
+// Unloads the outgoing state right away, when the stack changes (e.g. the game ends or a state is pushed) before the
+// transition finishes
+func (game *gameImpl) interruptTransition() {
+	game.unload(game.transition.from)
+	game.transition = nil
+}
+
+func (game *gameImpl) drawTransition(graphics ui.Graphics, alpha float64) {
+	game.transition.transition.Draw(graphics, game.settings.Width, game.settings.Height, game.transition.progress(),
+		func() { game.drawState(game.transition.from.state, graphics, alpha) },
+		func() { game.drawState(game.current(), graphics, alpha) })
+}
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/transition"
+	"testing"
+	"time"
+)
+
+func TestCrossfade(t *testing.T) {
+	var log []string
+	from, to := &lifecycleState{name: "from", log: &log}, &lifecycleState{name: "to", log: &log}
+	game := newTestGame(t, from, func(s *settings.Settings) {})
+	log = nil
+
+	if _, err := game.apply(state.WithTransition(to, transition.Crossfade(100*time.Millisecond))); err != nil {
+		t.Fatal(err)
+	}
+	if game.transition == nil || game.current() != to || fmt.Sprint(log) != "[load to]" {
+		t.Fatalf("the transition didn't start with the incoming state loaded: %v", log)
+	}
+
+	//Both states are frozen, and the outgoing one stays loaded until the end
+	for i := 0; i < 2; i++ {
+		nextState, _ := game.update(50 * time.Millisecond)
+		if nextState != to {
+			t.Fatalf("update returned %v during the transition, want the incoming state", nextState)
+		}
+		if _, err := game.apply(nextState); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if game.transition != nil || fmt.Sprint(log) != "[load to unload from]" {
+		t.Fatalf("the transition didn't finish after its duration: %v", log)
+	}
+
+	if running, _ := game.apply(nil); running || fmt.Sprint(log) != "[load to unload from unload to]" {
+		t.Errorf("log = %v, want both states unloaded", log)
+	}
+}
+
+func TestTransitionInterrupted(t *testing.T) {
+	tests := []struct {
+		name  string
+		next  func(log *[]string) state.State
+		log   []string
+		depth int
+	}{
+		{"push", func(log *[]string) state.State {
+			return state.Push(&lifecycleState{name: "child", log: log})
+		}, []string{"unload from", "load child", "pause to"}, 2},
+		{"pop", func(log *[]string) state.State { return state.Pop() }, []string{"unload from", "unload to"}, 1},
+		{"end of the game", func(log *[]string) state.State { return nil }, []string{"unload from", "unload to"}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var log []string
+			from, to := &lifecycleState{name: "from", log: &log}, &lifecycleState{name: "to", log: &log}
+			game := newTestGame(t, from, func(s *settings.Settings) {})
+			if _, err := game.apply(state.WithTransition(to, transition.Crossfade(time.Second))); err != nil {
+				t.Fatal(err)
+			}
+			log = nil
+
+			if _, err := game.apply(test.next(&log)); err != nil {
+				t.Fatal(err)
+			}
+			if game.transition != nil {
+				t.Error("the transition is still running")
+			}
+			if fmt.Sprint(log) != fmt.Sprint(test.log) || len(game.states) != test.depth {
+				t.Errorf("log = %v with %d states, want %v with %d", log, len(game.states), test.log, test.depth)
+			}
+		})
+	}
+}
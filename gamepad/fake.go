@@ -0,0 +1,68 @@
+package gamepad
+
+import (
+	"sort"
+	"sync"
+)
+
+// FakePoller is a Poller controlled by code instead of physical devices, for tests and headless runs. It can be changed
+// from any goroutine.
+type FakePoller struct {
+	mutex    sync.Mutex
+	gamepads map[ID]*Gamepad
+}
+
+func NewFakePoller() *FakePoller {
+	return &FakePoller{gamepads: make(map[ID]*Gamepad)}
+}
+
+func (f *FakePoller) Poll() []Gamepad {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	gamepads := make([]Gamepad, 0, len(f.gamepads))
+	for _, gamepad := range f.gamepads {
+		gamepads = append(gamepads, *gamepad)
+	}
+
+	sort.Slice(gamepads, func(i, j int) bool {
+		return gamepads[i].ID < gamepads[j].ID
+	})
+	return gamepads
+}
+
+func (f *FakePoller) Connect(id ID, name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.gamepads[id] = &Gamepad{ID: id, Name: name}
+}
+
+func (f *FakePoller) Disconnect(id ID) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.gamepads, id)
+}
+
+// SetButton changes a button of a connected gamepad; it does nothing if the gamepad isn't connected or the button
+// doesn't exist.
+func (f *FakePoller) SetButton(id ID, button Button, pressed bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if gamepad, isConnected := f.gamepads[id]; isConnected && button < ButtonCount {
+		gamepad.State.Buttons[button] = pressed
+	}
+}
+
+// SetAxis changes an axis of a connected gamepad; it does nothing if the gamepad isn't connected or the axis doesn't
+// exist.
+func (f *FakePoller) SetAxis(id ID, axis Axis, value float32) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if gamepad, isConnected := f.gamepads[id]; isConnected && axis < AxisCount {
+		gamepad.State.Axes[axis] = value
+	}
+}
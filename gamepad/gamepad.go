@@ -0,0 +1,61 @@
+package gamepad
+
+// ID identifies a connected gamepad (the slot it occupies), from 0 to 15.
+type ID byte
+
+// Button of the standard gamepad mapping (the layout of an Xbox controller)
+type Button byte
+
+const (
+	A            Button = 0
+	B            Button = 1
+	X            Button = 2
+	Y            Button = 3
+	LEFT_BUMPER  Button = 4
+	RIGHT_BUMPER Button = 5
+	BACK         Button = 6
+	START        Button = 7
+	GUIDE        Button = 8
+	LEFT_THUMB   Button = 9
+	RIGHT_THUMB  Button = 10
+	DPAD_UP      Button = 11
+	DPAD_RIGHT   Button = 12
+	DPAD_DOWN    Button = 13
+	DPAD_LEFT    Button = 14
+
+	ButtonCount = 15
+)
+
+// Axis of the standard gamepad mapping. The sticks go from -1 to 1, with positive values to the right and up (like the
+// screen coordinates); the triggers go from 0 (released) to 1 (fully pressed).
+type Axis byte
+
+const (
+	LEFT_X        Axis = 0
+	LEFT_Y        Axis = 1
+	RIGHT_X       Axis = 2
+	RIGHT_Y       Axis = 3
+	LEFT_TRIGGER  Axis = 4
+	RIGHT_TRIGGER Axis = 5
+
+	AxisCount = 6
+)
+
+// State holds the buttons and axes of a gamepad at a given moment.
+type State struct {
+	Buttons [ButtonCount]bool
+	Axes    [AxisCount]float32
+}
+
+// Gamepad is a connected controller with the standard mapping.
+type Gamepad struct {
+	ID    ID
+	Name  string
+	State State
+}
+
+// Poller reads the current state of the connected gamepads. It is implemented by the window backends, and can be faked
+// (see FakePoller) to test without a physical device.
+type Poller interface {
+	Poll() []Gamepad
+}
@@ -0,0 +1,19 @@
+package gamepad
+
+type Listener interface {
+	GamepadButtonPressed(id ID, button Button)
+	GamepadButtonReleased(id ID, button Button)
+	// GamepadAxisMoved is called whenever the value of an axis changes, after applying the dead zone.
+	GamepadAxisMoved(id ID, axis Axis, value float32)
+}
+
+type ConnectionListener interface {
+	GamepadConnected(id ID, name string)
+	GamepadDisconnected(id ID)
+}
+
+// EventListener receives all the gamepad events.
+type EventListener interface {
+	Listener
+	ConnectionListener
+}
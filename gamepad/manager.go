@@ -0,0 +1,106 @@
+package gamepad
+
+import (
+	"math"
+	"sort"
+)
+
+// Manager polls the gamepads once per frame and turns the changes since the last poll into events.
+type Manager struct {
+	poller    Poller
+	deadZones [AxisCount]float32
+
+	connected map[ID]Gamepad
+}
+
+// NewManager creates a Manager using the same dead zone for every axis: values closer to the rest position than the
+// dead zone are reported as the rest position, and the remaining range is scaled to still reach the extremes.
+func NewManager(poller Poller, deadZone float32) *Manager {
+	m := &Manager{poller: poller, connected: make(map[ID]Gamepad)}
+	for axis := range m.deadZones {
+		m.deadZones[axis] = deadZone
+	}
+	return m
+}
+
+// SetDeadZone changes the dead zone of a single axis; it does nothing if the axis doesn't exist.
+func (m *Manager) SetDeadZone(axis Axis, deadZone float32) {
+	if axis < AxisCount {
+		m.deadZones[axis] = deadZone
+	}
+}
+
+// Connected returns the gamepads connected in the last poll, with the dead zones already applied.
+func (m *Manager) Connected() []Gamepad {
+	gamepads := make([]Gamepad, 0, len(m.connected))
+	for _, gamepad := range m.connected {
+		gamepads = append(gamepads, gamepad)
+	}
+
+	sort.Slice(gamepads, func(i, j int) bool {
+		return gamepads[i].ID < gamepads[j].ID
+	})
+	return gamepads
+}
+
+// Update polls the gamepads and sends the connections, disconnections, presses, releases and axis changes since the
+// last call to the listener.
+func (m *Manager) Update(listener EventListener) {
+	polled := make(map[ID]bool)
+
+	for _, gamepad := range m.poller.Poll() {
+		polled[gamepad.ID] = true
+		for axis := range gamepad.State.Axes {
+			gamepad.State.Axes[axis] = applyDeadZone(gamepad.State.Axes[axis], m.deadZones[axis])
+		}
+
+		previous, wasConnected := m.connected[gamepad.ID]
+		m.connected[gamepad.ID] = gamepad
+
+		if !wasConnected {
+			listener.GamepadConnected(gamepad.ID, gamepad.Name)
+			previous = Gamepad{ID: gamepad.ID} //Report everything that isn't at rest
+		}
+
+		for button, pressed := range gamepad.State.Buttons {
+			if pressed && !previous.State.Buttons[button] {
+				listener.GamepadButtonPressed(gamepad.ID, Button(button))
+			} else if !pressed && previous.State.Buttons[button] {
+				listener.GamepadButtonReleased(gamepad.ID, Button(button))
+			}
+		}
+
+		for axis, value := range gamepad.State.Axes {
+			if value != previous.State.Axes[axis] {
+				listener.GamepadAxisMoved(gamepad.ID, Axis(axis), value)
+			}
+		}
+	}
+
+	for id := range m.connected {
+		if !polled[id] {
+			delete(m.connected, id)
+			listener.GamepadDisconnected(id)
+		}
+	}
+}
+
+func applyDeadZone(value float32, deadZone float32) float32 {
+	if deadZone <= 0 {
+		return value
+	}
+	if deadZone >= 1 {
+		return 0
+	}
+
+	magnitude := float32(math.Abs(float64(value)))
+	if magnitude <= deadZone {
+		return 0
+	}
+
+	scaled := (magnitude - deadZone) / (1 - deadZone)
+	if value < 0 {
+		return -scaled
+	}
+	return scaled
+}
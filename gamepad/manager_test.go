@@ -0,0 +1,137 @@
+package gamepad
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// Records the events as text, in the order they are received
+type recordingListener struct {
+	events []string
+}
+
+func (l *recordingListener) GamepadConnected(id ID, name string) {
+	l.events = append(l.events, fmt.Sprintf("connected %d %s", id, name))
+}
+
+func (l *recordingListener) GamepadDisconnected(id ID) {
+	l.events = append(l.events, fmt.Sprintf("disconnected %d", id))
+}
+
+func (l *recordingListener) GamepadButtonPressed(id ID, button Button) {
+	l.events = append(l.events, fmt.Sprintf("pressed %d %d", id, button))
+}
+
+func (l *recordingListener) GamepadButtonReleased(id ID, button Button) {
+	l.events = append(l.events, fmt.Sprintf("released %d %d", id, button))
+}
+
+func (l *recordingListener) GamepadAxisMoved(id ID, axis Axis, value float32) {
+	l.events = append(l.events, fmt.Sprintf("moved %d %d %.2f", id, axis, value))
+}
+
+func (l *recordingListener) take() []string {
+	events := l.events
+	l.events = nil
+	return events
+}
+
+func TestManagerEvents(t *testing.T) {
+	poller := NewFakePoller()
+	manager := NewManager(poller, 0)
+	listener := &recordingListener{}
+
+	steps := []struct {
+		name   string
+		change func()
+		events []string
+	}{
+		{"nothing connected", func() {}, nil},
+		{"connect", func() { poller.Connect(1, "pad") }, []string{"connected 1 pad"}},
+		{"press", func() { poller.SetButton(1, A, true) }, []string{"pressed 1 0"}},
+		{"hold", func() {}, nil},
+		{"move", func() { poller.SetAxis(1, LEFT_X, 0.5) }, []string{"moved 1 0 0.50"}},
+		{"release", func() { poller.SetButton(1, A, false) }, []string{"released 1 0"}},
+		{"disconnect", func() { poller.Disconnect(1) }, []string{"disconnected 1"}},
+	}
+
+	for _, step := range steps {
+		step.change()
+		manager.Update(listener)
+
+		events := listener.take()
+		if fmt.Sprint(events) != fmt.Sprint(step.events) {
+			t.Errorf("%s: got events %v, want %v", step.name, events, step.events)
+		}
+	}
+}
+
+func TestManagerReportsStateOnConnection(t *testing.T) {
+	poller := NewFakePoller()
+	poller.Connect(0, "pad")
+	poller.SetButton(0, START, true)
+	poller.SetAxis(0, RIGHT_TRIGGER, 1)
+
+	listener := &recordingListener{}
+	NewManager(poller, 0).Update(listener)
+
+	want := []string{"connected 0 pad", "pressed 0 7", "moved 0 5 1.00"}
+	if fmt.Sprint(listener.events) != fmt.Sprint(want) {
+		t.Errorf("got events %v, want %v", listener.events, want)
+	}
+}
+
+func TestApplyDeadZone(t *testing.T) {
+	tests := []struct {
+		value    float32
+		deadZone float32
+		want     float32
+	}{
+		{0.1, 0.2, 0},
+		{-0.2, 0.2, 0},
+		{0.6, 0.2, 0.5},
+		{-0.6, 0.2, -0.5},
+		{1, 0.2, 1},
+		{-1, 0.2, -1},
+		{0.3, 0, 0.3},
+		{0.9, 1, 0},
+	}
+
+	for _, test := range tests {
+		got := applyDeadZone(test.value, test.deadZone)
+		if math.Abs(float64(got-test.want)) > 1e-6 {
+			t.Errorf("applyDeadZone(%v, %v) = %v, want %v", test.value, test.deadZone, got, test.want)
+		}
+	}
+}
+
+func TestManagerDeadZonePerAxis(t *testing.T) {
+	poller := NewFakePoller()
+	poller.Connect(0, "pad")
+	poller.SetAxis(0, LEFT_X, 0.1)
+	poller.SetAxis(0, LEFT_Y, 0.1)
+
+	manager := NewManager(poller, 0.2)
+	manager.SetDeadZone(LEFT_Y, 0)
+	manager.SetDeadZone(AxisCount, 0.5) //Ignored
+	manager.Update(&recordingListener{})
+
+	axes := manager.Connected()[0].State.Axes
+	if axes[LEFT_X] != 0 || axes[LEFT_Y] != 0.1 {
+		t.Errorf("got axes %v, want only the left Y inside the dead zone", axes)
+	}
+}
+
+func TestFakePollerIgnoresInvalidInput(t *testing.T) {
+	poller := NewFakePoller()
+	poller.SetButton(0, A, true) //Not connected
+	poller.Connect(0, "pad")
+	poller.SetButton(0, ButtonCount, true)
+	poller.SetAxis(0, AxisCount, 1)
+
+	gamepads := poller.Poll()
+	if len(gamepads) != 1 || gamepads[0].State != (State{}) {
+		t.Errorf("got %+v, want a single gamepad at rest", gamepads)
+	}
+}
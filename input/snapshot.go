@@ -0,0 +1,174 @@
+package input
+
+import (
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+)
+
+// Snapshot is the state of the input devices in the current frame. The events received while a frame runs are only
+// taken into account in the next one (when Advance is called), so the values queried during an update never change
+// halfway through it. With a fixed update step, the game advances the snapshot before each update, so a "frame" here
+// is an update: an event is seen by exactly one of them, even if a frame runs several updates or none.
+type Snapshot struct {
+	current frame
+	pending frame
+
+	gamepads []gamepad.Gamepad
+	//The first position of the cursor doesn't count as a movement
+	cursorKnown bool
+}
+
+// Everything known about the input in a frame
+type frame struct {
+	keysDown     map[key.Key]bool
+	keysPressed  map[key.Key]bool
+	keysReleased map[key.Key]bool
+
+	buttonsDown     map[cursor.Button]bool
+	buttonsPressed  map[cursor.Button]bool
+	buttonsReleased map[cursor.Button]bool
+
+	cursorX int
+	cursorY int
+	deltaX  int
+	deltaY  int
+	scrollX float64
+	scrollY float64
+}
+
+func newFrame() frame {
+	return frame{
+		keysDown:        make(map[key.Key]bool),
+		keysPressed:     make(map[key.Key]bool),
+		keysReleased:    make(map[key.Key]bool),
+		buttonsDown:     make(map[cursor.Button]bool),
+		buttonsPressed:  make(map[cursor.Button]bool),
+		buttonsReleased: make(map[cursor.Button]bool),
+	}
+}
+
+func NewSnapshot() *Snapshot {
+	return &Snapshot{current: newFrame(), pending: newFrame()}
+}
+
+// Advance makes the events received since the last call visible, starting a new frame.
+func (s *Snapshot) Advance() {
+	s.current, s.pending = s.pending, s.current
+
+	//The pending frame starts from the state at the end of the current one, without the per-frame changes
+	copyMap(s.pending.keysDown, s.current.keysDown)
+	clearMap(s.pending.keysPressed)
+	clearMap(s.pending.keysReleased)
+
+	copyMap(s.pending.buttonsDown, s.current.buttonsDown)
+	clearMap(s.pending.buttonsPressed)
+	clearMap(s.pending.buttonsReleased)
+
+	s.pending.cursorX = s.current.cursorX
+	s.pending.cursorY = s.current.cursorY
+	s.pending.deltaX = 0
+	s.pending.deltaY = 0
+	s.pending.scrollX = 0
+	s.pending.scrollY = 0
+}
+
+// SetGamepads replaces the gamepads seen in the current frame.
+func (s *Snapshot) SetGamepads(gamepads []gamepad.Gamepad) {
+	s.gamepads = gamepads
+}
+
+func (s *Snapshot) IsDown(k key.Key) bool {
+	return s.current.keysDown[k]
+}
+
+// JustPressed returns whether the key was pressed since the last frame (even if it was released right after).
+func (s *Snapshot) JustPressed(k key.Key) bool {
+	return s.current.keysPressed[k]
+}
+
+// JustReleased returns whether the key was released since the last frame.
+func (s *Snapshot) JustReleased(k key.Key) bool {
+	return s.current.keysReleased[k]
+}
+
+func (s *Snapshot) IsButtonDown(button cursor.Button) bool {
+	return s.current.buttonsDown[button]
+}
+
+func (s *Snapshot) ButtonJustPressed(button cursor.Button) bool {
+	return s.current.buttonsPressed[button]
+}
+
+func (s *Snapshot) ButtonJustReleased(button cursor.Button) bool {
+	return s.current.buttonsReleased[button]
+}
+
+// Cursor returns the position of the cursor, with the origin at the bottom left corner of the screen.
+func (s *Snapshot) Cursor() (int, int) {
+	return s.current.cursorX, s.current.cursorY
+}
+
+// CursorDelta returns how much the cursor moved since the last frame.
+func (s *Snapshot) CursorDelta() (int, int) {
+	return s.current.deltaX, s.current.deltaY
+}
+
+// Scroll returns the sum of the scroll offsets since the last frame.
+func (s *Snapshot) Scroll() (float64, float64) {
+	return s.current.scrollX, s.current.scrollY
+}
+
+// Gamepads returns the connected gamepads and their state in this frame.
+func (s *Snapshot) Gamepads() []gamepad.Gamepad {
+	return s.gamepads
+}
+
+func (s *Snapshot) KeyEvent(event key.Event) {
+	switch event.Action {
+	case key.PRESS:
+		s.pending.keysDown[event.Key] = true
+		s.pending.keysPressed[event.Key] = true
+	case key.RELEASE:
+		delete(s.pending.keysDown, event.Key)
+		s.pending.keysReleased[event.Key] = true
+	}
+}
+
+func (s *Snapshot) ButtonPressed(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	s.pending.buttonsDown[button] = true
+	s.pending.buttonsPressed[button] = true
+}
+
+func (s *Snapshot) ButtonReleased(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	delete(s.pending.buttonsDown, button)
+	s.pending.buttonsReleased[button] = true
+}
+
+func (s *Snapshot) CursorMoved(x int, y int) {
+	if s.cursorKnown {
+		s.pending.deltaX += x - s.pending.cursorX
+		s.pending.deltaY += y - s.pending.cursorY
+	}
+	s.cursorKnown = true
+	s.pending.cursorX = x
+	s.pending.cursorY = y
+}
+
+func (s *Snapshot) Scrolled(xOffset float64, yOffset float64) {
+	s.pending.scrollX += xOffset
+	s.pending.scrollY += yOffset
+}
+
+func copyMap[K comparable](destination map[K]bool, source map[K]bool) {
+	clearMap(destination)
+	for k, v := range source {
+		destination[k] = v
+	}
+}
+
+func clearMap[K comparable](m map[K]bool) {
+	for k := range m {
+		delete(m, k)
+	}
+}
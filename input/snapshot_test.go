@@ -0,0 +1,75 @@
+package input
+
+import (
+	"github.com/Hikarikun92/go-game-engine/key"
+	"testing"
+)
+
+func TestSnapshotKeys(t *testing.T) {
+	s := NewSnapshot()
+
+	steps := []struct {
+		name                    string
+		events                  []key.Action
+		down, pressed, released bool
+	}{
+		{"idle", nil, false, false, false},
+		{"press", []key.Action{key.PRESS}, true, true, false},
+		{"hold", nil, true, false, false},
+		{"release", []key.Action{key.RELEASE}, false, false, true},
+		{"tap within a frame", []key.Action{key.PRESS, key.RELEASE}, false, true, true},
+		{"after the tap", nil, false, false, false},
+	}
+
+	var wasDown bool
+	for _, step := range steps {
+		for _, action := range step.events {
+			s.KeyEvent(key.Event{Key: key.SPACE, Action: action})
+		}
+		if s.IsDown(key.SPACE) != wasDown {
+			t.Fatalf("%s: the events were visible before Advance", step.name)
+		}
+		s.Advance()
+		wasDown = step.down
+
+		down, pressed, released := s.IsDown(key.SPACE), s.JustPressed(key.SPACE), s.JustReleased(key.SPACE)
+		if down != step.down || pressed != step.pressed || released != step.released {
+			t.Errorf("%s: down %v, pressed %v, released %v; want %v, %v, %v", step.name, down, pressed, released,
+				step.down, step.pressed, step.released)
+		}
+	}
+}
+
+func TestSnapshotCursor(t *testing.T) {
+	s := NewSnapshot()
+
+	//The first position isn't a movement
+	s.CursorMoved(10, 10)
+	s.Advance()
+	if dx, dy := s.CursorDelta(); dx != 0 || dy != 0 {
+		t.Errorf("delta after the first position = %d, %d, want 0, 0", dx, dy)
+	}
+
+	s.CursorMoved(15, 12)
+	s.CursorMoved(20, 8)
+	s.Scrolled(0, 1)
+	s.Scrolled(0, 2)
+	s.Advance()
+	if x, y := s.Cursor(); x != 20 || y != 8 {
+		t.Errorf("cursor = %d, %d, want 20, 8", x, y)
+	}
+	if dx, dy := s.CursorDelta(); dx != 10 || dy != -2 {
+		t.Errorf("delta = %d, %d, want 10, -2", dx, dy)
+	}
+	if _, y := s.Scroll(); y != 3 {
+		t.Errorf("scroll = %v, want 3", y)
+	}
+
+	s.Advance()
+	if dx, dy := s.CursorDelta(); dx != 0 || dy != 0 {
+		t.Errorf("delta without movement = %d, %d, want 0, 0", dx, dy)
+	}
+	if x, y := s.Cursor(); x != 20 || y != 8 {
+		t.Errorf("cursor moved to %d, %d without events", x, y)
+	}
+}
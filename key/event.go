@@ -0,0 +1,26 @@
+package key
+
+type Action byte
+
+const (
+	PRESS   Action = 0
+	RELEASE Action = 1
+	//Generated by the operating system while a key is held down
+	REPEAT Action = 2
+)
+
+// Event holds all the details of something that happened to a key.
+type Event struct {
+	Key    Key
+	Action Action
+	//Platform-specific code of the physical key, available even if Key is UNKNOWN
+	Scancode  int
+	Modifiers Modifier
+}
+
+// EventListener is a more detailed alternative to Listener: besides the presses and releases, it receives the repeated
+// presses while a key is held down, along with the scancode and the modifiers of each event. It also receives the keys
+// the engine doesn't know, as UNKNOWN.
+type EventListener interface {
+	KeyEvent(event Event)
+}
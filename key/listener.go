@@ -4,3 +4,9 @@ type Listener interface {
 	KeyPressed(k Key)
 	KeyReleased(k Key)
 }
+
+// TextListener receives the characters typed by the player, after applying the keyboard layout and modifiers (unlike
+// Listener, which receives the physical keys). It is meant for typing text, such as names or chat messages.
+type TextListener interface {
+	CharacterTyped(char rune)
+}
@@ -0,0 +1,18 @@
+package key
+
+// Modifier is a set of modifier keys (and lock states) active when an input event happened.
+type Modifier byte
+
+const (
+	MOD_SHIFT     Modifier = 1
+	MOD_CONTROL   Modifier = 2
+	MOD_ALT       Modifier = 4
+	MOD_SUPER     Modifier = 8
+	MOD_CAPS_LOCK Modifier = 16
+	MOD_NUM_LOCK  Modifier = 32
+)
+
+// Has returns whether all the given modifiers are active.
+func (m Modifier) Has(modifiers Modifier) bool {
+	return m&modifiers == modifiers
+}
@@ -0,0 +1,173 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Load returns the default settings overlaid, in this order, by the player's config file (if it exists), the
+// environment variables and the command-line arguments, and validates the result. The name identifies the game: it is
+// the directory of the config file and, in upper case, the prefix of the environment variables (e.g. "MYGAME_WIDTH").
+func Load(name string, args []string) (*Settings, error) {
+	settings := DefaultSettings()
+
+	file, err := ConfigFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.LoadFile(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	if err := settings.LoadEnv(EnvPrefix(name)); err != nil {
+		return nil, err
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	settings.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	return settings, nil
+}
+
+// ConfigFile returns where the settings of the game are saved, inside the platform's config directory (e.g.
+// ~/.config/<name>/settings.json on Linux and %AppData%\<name>\settings.json on Windows).
+func ConfigFile(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the config directory: %w", err)
+	}
+	return filepath.Join(dir, name, "settings.json"), nil
+}
+
+// EnvPrefix returns the prefix of the environment variables of a game, which is its name in upper case with anything
+// other than letters and digits replaced by underscores.
+func EnvPrefix(name string) string {
+	prefix := strings.Map(func(char rune) rune {
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') {
+			return char
+		}
+		return '_'
+	}, name)
+	return strings.ToUpper(prefix) + "_"
+}
+
+// Save writes the settings as JSON, including the keys that were read by Load but aren't known by this version of the
+// engine, so they aren't lost when older and newer versions of a game share the same file.
+func (s *Settings) Save(w io.Writer) error {
+	values := make(map[string]json.RawMessage, len(s.unknown)+len(options))
+	for name, raw := range s.unknown {
+		values[name] = raw
+	}
+
+	for _, o := range options {
+		raw, err := o.value(s).MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("failed to write setting %q: %w", o.name, err)
+		}
+		values[o.name] = raw
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(values)
+}
+
+// Load overlays the settings present in the JSON written by Save. Settings that aren't in it keep their values, so
+// settings added to the engine still have their defaults with an old file.
+func (s *Settings) Load(r io.Reader) error {
+	var values map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+
+	//Only change the settings once the whole file is known to be valid
+	loaded := *s
+	loaded.unknown = make(map[string]json.RawMessage)
+	for name, raw := range s.unknown {
+		loaded.unknown[name] = raw
+	}
+
+	for name, raw := range values {
+		o, isKnown := findOption(name)
+		if !isKnown {
+			loaded.unknown[name] = raw
+			continue
+		}
+		if err := o.value(&loaded).UnmarshalJSON(raw); err != nil {
+			return fmt.Errorf("invalid value for setting %q: %w", name, err)
+		}
+	}
+
+	*s = loaded
+	return nil
+}
+
+// SaveFile writes the settings to a file, creating its directory if needed.
+func (s *Settings) SaveFile(file string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory for %q: %w", file, err)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create settings file %q: %w", file, err)
+	}
+
+	if err := s.Save(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save settings file %q: %w", file, err)
+	}
+	return f.Close()
+}
+
+// LoadFile reads the settings from a file. The error wraps fs.ErrNotExist if the file doesn't exist, which usually
+// means that the game is running for the first time.
+func (s *Settings) LoadFile(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open settings file %q: %w", file, err)
+	}
+	defer f.Close()
+
+	if err := s.Load(f); err != nil {
+		return fmt.Errorf("failed to load settings file %q: %w", file, err)
+	}
+	return nil
+}
+
+// LoadEnv overlays the settings present in the environment, each one in a variable named as its key in the config
+// file in upper case, after the prefix (e.g. "MYGAME_WINDOW_MODE=fullscreen").
+func (s *Settings) LoadEnv(prefix string) error {
+	for _, o := range options {
+		variable := prefix + strings.ToUpper(o.name)
+		text, isSet := os.LookupEnv(variable)
+		if !isSet {
+			continue
+		}
+		if err := o.value(s).Set(text); err != nil {
+			return fmt.Errorf("invalid value for environment variable %s: %w", variable, err)
+		}
+	}
+	return nil
+}
+
+// RegisterFlags adds a flag for each setting, named as its key in the config file with hyphens instead of underscores
+// (e.g. "-window-mode=fullscreen"). The settings are changed when the flags are parsed.
+func (s *Settings) RegisterFlags(flags *flag.FlagSet) {
+	for _, o := range options {
+		flags.Var(o.value(s), strings.ReplaceAll(o.name, "_", "-"), o.usage)
+	}
+}
@@ -0,0 +1,216 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Keys written by other versions of the game are kept, so saving with this version doesn't lose them
+func TestUnknownKeys(t *testing.T) {
+	s := DefaultSettings()
+	files := []string{
+		`{"width": 640, "music_volume": 0.5, "keybinds": {"jump": "space"}}`,
+		`{"music_volume": 0.8, "language": "pt-BR"}`,
+	}
+	for _, file := range files {
+		if err := s.Load(strings.NewReader(file)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s.Width != 640 {
+		t.Errorf("width = %d, want 640", s.Width)
+	}
+
+	var buffer bytes.Buffer
+	if err := s.Save(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]json.RawMessage
+	if err := json.Unmarshal(buffer.Bytes(), &saved); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"music_volume": `0.8`, //The last file wins
+		"language":     `"pt-BR"`,
+		"keybinds":     `{"jump":"space"}`,
+		"width":        `640`,
+	}
+	for key, value := range want {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, saved[key]); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if compact.String() != value {
+			t.Errorf("%s = %s, want %s", key, compact.String(), value)
+		}
+	}
+
+	//A file rejected because of a known key doesn't add its unknown keys either
+	if err := s.Load(strings.NewReader(`{"difficulty": "hard", "width": "wide"}`)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, found := s.unknown["difficulty"]; found {
+		t.Error("the unknown key of an invalid file was kept")
+	}
+}
+
+// Enumerations and durations are saved as text the players can edit
+func TestSaveReadableValues(t *testing.T) {
+	s := DefaultSettings()
+	s.WindowMode = BORDERLESS
+	s.FixedUpdateStep = 10 * time.Millisecond
+
+	var buffer bytes.Buffer
+	if err := s.Save(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{`"window_mode": "borderless"`, `"fixed_update_step": "10ms"`} {
+		if !strings.Contains(buffer.String(), value) {
+			t.Errorf("%s not found in %s", value, buffer.String())
+		}
+	}
+
+	loaded := DefaultSettings()
+	if err := loaded.Load(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !sameValues(loaded, s) {
+		t.Errorf("loaded %+v, want %+v", loaded, s)
+	}
+}
+
+// Compares the known settings, ignoring the unknown keys
+func sameValues(a *Settings, b *Settings) bool {
+	aValues, bValues := *a, *b
+	aValues.unknown, bValues.unknown = nil, nil
+	return reflect.DeepEqual(aValues, bValues)
+}
+
+// Uses a new config directory for the game "my-game", with the given config file (if any)
+func setUpConfig(t *testing.T, contents string) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	if contents == "" {
+		return
+	}
+
+	file, err := ConfigFile("my-game")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The file is overlaid by the environment, which is overlaid by the command line
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  string
+		flag string
+		want WindowMode
+	}{
+		{"defaults", "", "", "", WINDOWED},
+		{"file", `{"window_mode": "borderless"}`, "", "", BORDERLESS},
+		{"environment over file", `{"window_mode": "borderless"}`, "fullscreen", "", FULLSCREEN},
+		{"flag over environment", "", "fullscreen", "windowed", WINDOWED},
+		{"flag over file and environment", `{"window_mode": "fullscreen"}`, "fullscreen", "borderless", BORDERLESS},
+		{"empty environment variable", `{"window_mode": "borderless"}`, "", "", BORDERLESS},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setUpConfig(t, test.file)
+			if test.env != "" {
+				t.Setenv("MY_GAME_WINDOW_MODE", test.env)
+			}
+			var args []string
+			if test.flag != "" {
+				args = []string{"-window-mode=" + test.flag}
+			}
+
+			s, err := Load("my-game", args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.WindowMode != test.want {
+				t.Errorf("window mode = %d, want %d", s.WindowMode, test.want)
+			}
+		})
+	}
+}
+
+// Each source can set a different part of the settings
+func TestLoadMergesSources(t *testing.T) {
+	setUpConfig(t, `{"width": 1024, "height": 768, "monitor": 1}`)
+	t.Setenv("MY_GAME_HEIGHT", "720")
+
+	s, err := Load("my-game", []string{"-resizable", "-fixed-update-step=5ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := DefaultSettings()
+	want.Width, want.Height, want.Monitor = 1024, 720, 1
+	want.Resizable, want.FixedUpdateStep = true, 5*time.Millisecond
+	if !sameValues(s, want) {
+		t.Errorf("got %+v, want %+v", s, want)
+	}
+}
+
+func TestLoadSourceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  string
+		args []string
+		//Part of the error, which must tell the player where to look
+		message string
+	}{
+		{"file", `{"window_mode": "maximized"}`, "", nil, "settings.json"},
+		{"environment", "", "maximized", nil, "MY_GAME_WINDOW_MODE"},
+		{"flag", "", "", []string{"-window-mode=maximized"}, "window-mode"},
+		{"unknown flag", "", "", []string{"-volume=5"}, "volume"},
+		{"invalid result", `{"width": -5}`, "", nil, "invalid settings"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setUpConfig(t, test.file)
+			if test.env != "" {
+				t.Setenv("MY_GAME_WINDOW_MODE", test.env)
+			}
+
+			_, err := Load("my-game", test.args)
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Errorf("got error %v, want one mentioning %q", err, test.message)
+			}
+		})
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	tests := map[string]string{
+		"game":       "GAME_",
+		"my-game":    "MY_GAME_",
+		"Space Rock": "SPACE_ROCK_",
+		"r2d2":       "R2D2_",
+	}
+	for name, want := range tests {
+		if got := EnvPrefix(name); got != want {
+			t.Errorf("EnvPrefix(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
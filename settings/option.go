@@ -0,0 +1,277 @@
+package settings
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A setting that can be read from the config file, the environment and the command line
+type option struct {
+	//Key in the config file; the flag and the environment variable are derived from it
+	name  string
+	usage string
+	value func(s *Settings) value
+}
+
+// A field of the settings, converted from and to the text of the flags and environment variables and from and to JSON
+type value interface {
+	flag.Value
+	json.Marshaler
+	json.Unmarshaler
+}
+
+var options = []option{
+	{"width", "logical width of the game", func(s *Settings) value { return &intValue[int]{&s.Width} }},
+	{"height", "logical height of the game", func(s *Settings) value { return &intValue[int]{&s.Height} }},
+	{"window_title", "title of the window", func(s *Settings) value { return &stringValue{&s.WindowTitle} }},
+	{"fps", "maximum frames per second", func(s *Settings) value { return &intValue[int]{&s.Fps} }},
+	{"frame_pacing", "capped, vsync or uncapped", func(s *Settings) value {
+		return &enumValue[FramePacing]{&s.FramePacing, framePacingNames}
+	}},
+	{"resizable", "whether the window can be resized", func(s *Settings) value { return &boolValue{&s.Resizable} }},
+	{"scaling", "stretch, fit or integer", func(s *Settings) value {
+		return &enumValue[Scaling]{&s.Scaling, scalingNames}
+	}},
+	{"window_mode", "windowed, borderless or fullscreen", func(s *Settings) value {
+		return &enumValue[WindowMode]{&s.WindowMode, windowModeNames}
+	}},
+	{"monitor", "index of the monitor, 0 being the primary one", func(s *Settings) value { return &intValue[int]{&s.Monitor} }},
+	{"video_mode_width", "monitor width in fullscreen, 0 for the current one", func(s *Settings) value {
+		return &intValue[int]{&s.VideoMode.Width}
+	}},
+	{"video_mode_height", "monitor height in fullscreen, 0 for the current one", func(s *Settings) value {
+		return &intValue[int]{&s.VideoMode.Height}
+	}},
+	{"video_mode_refresh_rate", "monitor refresh rate in fullscreen, 0 for the current one", func(s *Settings) value {
+		return &intValue[int]{&s.VideoMode.RefreshRate}
+	}},
+	{"pause_on_focus_loss", "stop updating the game while the window doesn't have the focus", func(s *Settings) value {
+		return &boolValue{&s.PauseOnFocusLoss}
+	}},
+	{"fixed_update_step", "duration of each simulation step, 0 to update once per frame", func(s *Settings) value {
+		return &durationValue{&s.FixedUpdateStep}
+	}},
+	{"max_frame_time", "maximum time simulated in a single frame", func(s *Settings) value {
+		return &durationValue{&s.MaxFrameTime}
+	}},
+	{"missing_image_fallback", "replace images that fail to load by a placeholder", func(s *Settings) value {
+		return &boolValue{&s.MissingImageFallback}
+	}},
+	{"preload_workers", "goroutines decoding images, 0 for one per CPU", func(s *Settings) value {
+		return &intValue[int]{&s.PreloadWorkers}
+	}},
+	{"stats_overlay", "show the frame statistics overlay", func(s *Settings) value { return &boolValue{&s.StatsOverlay} }},
+	{"stats_overlay_key", "code of the key that toggles the statistics overlay", func(s *Settings) value {
+		return &intValue[byte]{(*byte)(&s.StatsOverlayKey)}
+	}},
+	{"double_click_interval", "maximum time between two clicks of a double click", func(s *Settings) value {
+		return &durationValue{&s.DoubleClickInterval}
+	}},
+	{"gamepad_dead_zone", "dead zone of the gamepad axes, from 0 to 1", func(s *Settings) value {
+		return &float32Value{&s.GamepadDeadZone}
+	}},
+}
+
+var framePacingNames = []string{CAPPED: "capped", VSYNC: "vsync", UNCAPPED: "uncapped"}
+var scalingNames = []string{STRETCH: "stretch", FIT: "fit", INTEGER: "integer"}
+var windowModeNames = []string{WINDOWED: "windowed", BORDERLESS: "borderless", FULLSCREEN: "fullscreen"}
+
+func findOption(name string) (option, bool) {
+	for _, o := range options {
+		if o.name == name {
+			return o, true
+		}
+	}
+	return option{}, false
+}
+
+type intValue[T int | byte] struct {
+	pointer *T
+}
+
+func (v *intValue[T]) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	return strconv.Itoa(int(*v.pointer))
+}
+
+func (v *intValue[T]) Set(text string) error {
+	parsed, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return err
+	}
+	if int64(T(parsed)) != parsed {
+		return fmt.Errorf("value %d out of range", parsed)
+	}
+	*v.pointer = T(parsed)
+	return nil
+}
+
+func (v *intValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*v.pointer)
+}
+
+func (v *intValue[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, v.pointer)
+}
+
+type stringValue struct {
+	pointer *string
+}
+
+func (v *stringValue) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	return *v.pointer
+}
+
+func (v *stringValue) Set(text string) error {
+	*v.pointer = text
+	return nil
+}
+
+func (v *stringValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*v.pointer)
+}
+
+func (v *stringValue) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, v.pointer)
+}
+
+type boolValue struct {
+	pointer *bool
+}
+
+func (v *boolValue) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v.pointer)
+}
+
+func (v *boolValue) Set(text string) error {
+	parsed, err := strconv.ParseBool(text)
+	if err != nil {
+		return err
+	}
+	*v.pointer = parsed
+	return nil
+}
+
+// IsBoolFlag allows the flag to be used without a value, e.g. "-resizable"
+func (v *boolValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *boolValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*v.pointer)
+}
+
+func (v *boolValue) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, v.pointer)
+}
+
+type float32Value struct {
+	pointer *float32
+}
+
+func (v *float32Value) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	return strconv.FormatFloat(float64(*v.pointer), 'g', -1, 32)
+}
+
+func (v *float32Value) Set(text string) error {
+	parsed, err := strconv.ParseFloat(text, 32)
+	if err != nil {
+		return err
+	}
+	*v.pointer = float32(parsed)
+	return nil
+}
+
+func (v *float32Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*v.pointer)
+}
+
+func (v *float32Value) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, v.pointer)
+}
+
+// Durations are written like time.ParseDuration expects them (e.g. "500ms"), so the file is readable by the players
+type durationValue struct {
+	pointer *time.Duration
+}
+
+func (v *durationValue) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	return v.pointer.String()
+}
+
+func (v *durationValue) Set(text string) error {
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*v.pointer = parsed
+	return nil
+}
+
+func (v *durationValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+func (v *durationValue) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return v.Set(text)
+}
+
+// Enumerations are written by name, with the names indexed by their values
+type enumValue[T ~byte] struct {
+	pointer *T
+	names   []string
+}
+
+func (v *enumValue[T]) String() string {
+	if v.pointer == nil {
+		return ""
+	}
+	if int(*v.pointer) < len(v.names) {
+		return v.names[*v.pointer]
+	}
+	return strconv.Itoa(int(*v.pointer))
+}
+
+func (v *enumValue[T]) Set(text string) error {
+	for i, name := range v.names {
+		if strings.EqualFold(name, text) {
+			*v.pointer = T(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown value %q, expected one of %s", text, strings.Join(v.names, ", "))
+}
+
+func (v *enumValue[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+func (v *enumValue[T]) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return v.Set(text)
+}
@@ -1,17 +1,154 @@
 package settings
 
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"time"
+)
+
+// Scaling defines how the game is drawn when the window's size is different from the game's resolution
+type Scaling byte
+
+const (
+	//Fills the whole window, distorting the image if the aspect ratio is different
+	STRETCH Scaling = 0
+	//Keeps the aspect ratio, filling the rest of the window with black bars
+	FIT Scaling = 1
+	//Like FIT, but only scales by whole numbers so every pixel has the same size (unless the window is smaller than
+	//the game's resolution)
+	INTEGER Scaling = 2
+)
+
+// WindowMode defines whether the game is shown in a window or takes the whole monitor
+type WindowMode byte
+
+const (
+	WINDOWED WindowMode = 0
+	//Takes the whole monitor without changing its video mode, so switching to other windows is fast
+	BORDERLESS WindowMode = 1
+	//Takes the whole monitor, changing it to the chosen video mode
+	FULLSCREEN WindowMode = 2
+)
+
+// VideoMode is the resolution and refresh rate used by the monitor in exclusive fullscreen. Zero values mean the
+// monitor's current ones.
+type VideoMode struct {
+	Width       int
+	Height      int
+	RefreshRate int
+}
+
+// FramePacing defines how the game waits between frames
+type FramePacing byte
+
+const (
+	//Limits the frames to Fps per second, sleeping between them
+	CAPPED FramePacing = 0
+	//Waits for the monitor's refresh before showing each frame, avoiding tearing
+	VSYNC FramePacing = 1
+	//Draws frames as fast as possible
+	UNCAPPED FramePacing = 2
+)
+
 type Settings struct {
+	//Logical resolution of the game, used by all the drawing and input coordinates, and initial size of the window
 	Width       int
 	Height      int
 	WindowTitle string
+	//Maximum frames per second when the pacing is CAPPED; with other pacings, zero or the expected rate, used as the
+	//budget shown in the statistics overlay
 	Fps         int
+	FramePacing FramePacing
+
+	Resizable bool
+	Scaling   Scaling
+
+	WindowMode WindowMode
+	//Index of the monitor where the window is shown, in the order reported by the system (the primary monitor comes
+	//first); it falls back to the primary monitor if there's no such monitor
+	Monitor   int
+	VideoMode VideoMode
+	//Whether the states stop being updated while the window doesn't have the focus
+	PauseOnFocusLoss bool
+
+	//Duration of each simulation step when using a fixed update rate; zero means that the state is updated once per
+	//frame with the real elapsed time
+	FixedUpdateStep time.Duration
+	//Maximum time simulated in a single frame when using a fixed update rate, to avoid doing too many updates (and
+	//falling behind even more) after a hitch; zero means DefaultMaxFrameTime. It can't be shorter than the step.
+	MaxFrameTime time.Duration
+
+	//Whether images that fail to load should be replaced by a placeholder instead of making the state fail to load
+	MissingImageFallback bool
+	//Amount of goroutines used to decode the images of states that preload them; zero means one per CPU
+	PreloadWorkers int
+
+	//Whether the frame statistics overlay is visible when the game starts
+	StatsOverlay bool
+	//Key that shows or hides the frame statistics overlay; key.UNKNOWN means that there's no such key
+	StatsOverlayKey key.Key
+
+	//Maximum time between two clicks of a double click
+	DoubleClickInterval time.Duration
+	//Dead zone applied to every gamepad axis, from 0 to 1
+	GamepadDeadZone float32
+
+	//Keys of the config file that this version of the engine doesn't know, written back when saving
+	unknown map[string]json.RawMessage
 }
 
+// DefaultMaxFrameTime is the maximum time simulated in a single frame when the settings don't have one.
+const DefaultMaxFrameTime = 250 * time.Millisecond
+
 func DefaultSettings() *Settings {
 	return &Settings{
-		Width:       800,
-		Height:      600,
-		WindowTitle: "Example game",
-		Fps:         60,
+		Width:        800,
+		Height:       600,
+		WindowTitle:  "Example game",
+		Fps:          60,
+		Scaling:      FIT,
+		MaxFrameTime: DefaultMaxFrameTime,
+
+		DoubleClickInterval: 500 * time.Millisecond,
+		GamepadDeadZone:     0.15,
+	}
+}
+
+// Validate returns an error describing the first value that makes the game unable to run, if any.
+func (s *Settings) Validate() error {
+	if s.Width <= 0 || s.Height <= 0 {
+		return fmt.Errorf("invalid resolution %dx%d", s.Width, s.Height)
+	}
+	if s.FramePacing > UNCAPPED {
+		return fmt.Errorf("invalid frame pacing %d", s.FramePacing)
+	}
+	if s.Fps < 0 || (s.Fps == 0 && s.FramePacing == CAPPED) {
+		return fmt.Errorf("invalid frame rate %d for the chosen frame pacing", s.Fps)
+	}
+	if s.FixedUpdateStep < 0 {
+		return fmt.Errorf("invalid fixed update step %v", s.FixedUpdateStep)
+	}
+	if s.MaxFrameTime < 0 || (s.MaxFrameTime > 0 && s.MaxFrameTime < s.FixedUpdateStep) {
+		return fmt.Errorf("invalid maximum frame time %v for a fixed update step of %v", s.MaxFrameTime, s.FixedUpdateStep)
+	}
+	if s.Scaling > INTEGER {
+		return fmt.Errorf("invalid scaling %d", s.Scaling)
+	}
+	if s.WindowMode > FULLSCREEN {
+		return fmt.Errorf("invalid window mode %d", s.WindowMode)
+	}
+	if s.VideoMode.Width < 0 || s.VideoMode.Height < 0 || s.VideoMode.RefreshRate < 0 {
+		return fmt.Errorf("invalid video mode %dx%d@%d", s.VideoMode.Width, s.VideoMode.Height, s.VideoMode.RefreshRate)
+	}
+	if s.PreloadWorkers < 0 {
+		return fmt.Errorf("invalid amount of preload workers %d", s.PreloadWorkers)
+	}
+	if s.DoubleClickInterval < 0 {
+		return fmt.Errorf("invalid double click interval %v", s.DoubleClickInterval)
+	}
+	if s.GamepadDeadZone < 0 || s.GamepadDeadZone > 1 {
+		return fmt.Errorf("invalid gamepad dead zone %v", s.GamepadDeadZone)
 	}
+	return nil
 }
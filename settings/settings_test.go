@@ -0,0 +1,41 @@
+package settings
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *Settings)
+		valid  bool
+	}{
+		{"defaults", func(s *Settings) {}, true},
+		{"no resolution", func(s *Settings) { s.Width = 0 }, false},
+		{"unknown frame pacing", func(s *Settings) { s.FramePacing = UNCAPPED + 1 }, false},
+		{"capped without a frame rate", func(s *Settings) { s.Fps = 0 }, false},
+		{"vsync without a frame rate", func(s *Settings) { s.Fps, s.FramePacing = 0, VSYNC }, true},
+		{"negative fixed step", func(s *Settings) { s.FixedUpdateStep = -time.Millisecond }, false},
+		{"fixed step without a maximum frame time", func(s *Settings) {
+			s.FixedUpdateStep, s.MaxFrameTime = 10*time.Millisecond, 0
+		}, true},
+		{"maximum frame time shorter than the step", func(s *Settings) {
+			s.FixedUpdateStep, s.MaxFrameTime = 10*time.Millisecond, 5*time.Millisecond
+		}, false},
+		{"unknown scaling", func(s *Settings) { s.Scaling = INTEGER + 1 }, false},
+		{"unknown window mode", func(s *Settings) { s.WindowMode = FULLSCREEN + 1 }, false},
+		{"negative video mode", func(s *Settings) { s.VideoMode.RefreshRate = -1 }, false},
+		{"dead zone over 1", func(s *Settings) { s.GamepadDeadZone = 1.5 }, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := DefaultSettings()
+			test.change(s)
+			if err := s.Validate(); (err == nil) != test.valid {
+				t.Errorf("Validate() = %v, want valid = %v", err, test.valid)
+			}
+		})
+	}
+}
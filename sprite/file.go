@@ -0,0 +1,169 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Representation of a sheet in the JSON exported by TexturePacker and Aseprite
+type sheetJson struct {
+	//Either an object with the frames by name or an array of frames with their names
+	Frames json.RawMessage `json:"frames"`
+	Meta   struct {
+		Image     string    `json:"image"`
+		FrameTags []tagJson `json:"frameTags"`
+	} `json:"meta"`
+}
+
+type frameJson struct {
+	Filename         string   `json:"filename"`
+	Frame            rectJson `json:"frame"`
+	Rotated          bool     `json:"rotated"`
+	Trimmed          bool     `json:"trimmed"`
+	SpriteSourceSize rectJson `json:"spriteSourceSize"`
+	SourceSize       rectJson `json:"sourceSize"`
+	Duration         int      `json:"duration"` //In milliseconds
+}
+
+type rectJson struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type tagJson struct {
+	Name      string `json:"name"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Direction string `json:"direction"`
+}
+
+// ParseSheet reads the JSON exported by TexturePacker or Aseprite (with the frames either as a hash or as an array) for
+// an image that is already loaded. Rotated frames aren't supported.
+func ParseSheet(r io.Reader, img ui.Image) (*Sheet, error) {
+	var description sheetJson
+	if err := json.NewDecoder(r).Decode(&description); err != nil {
+		return nil, fmt.Errorf("invalid sprite sheet: %w", err)
+	}
+	return newSheet(description, img)
+}
+
+// LoadSheet reads the JSON exported by TexturePacker or Aseprite and loads the image it refers to, relative to the
+// file. The image must be released with Unload.
+func LoadSheet(imageLoader ui.ImageLoader, file string) (*Sheet, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sprite sheet %q: %w", file, err)
+	}
+
+	var description sheetJson
+	if err := json.Unmarshal(data, &description); err != nil {
+		return nil, fmt.Errorf("failed to load sprite sheet %q: invalid sprite sheet: %w", file, err)
+	}
+	if description.Meta.Image == "" {
+		return nil, fmt.Errorf("failed to load sprite sheet %q: no image", file)
+	}
+
+	img, err := imageLoader.LoadImage(filepath.Join(filepath.Dir(file), description.Meta.Image))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sprite sheet %q: %w", file, err)
+	}
+
+	sheet, err := newSheet(description, img)
+	if err != nil {
+		imageLoader.UnloadImage(img)
+		return nil, fmt.Errorf("failed to load sprite sheet %q: %w", file, err)
+	}
+	return sheet, nil
+}
+
+func newSheet(description sheetJson, img ui.Image) (*Sheet, error) {
+	frames, err := parseFrames(description.Frames)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sprite sheet: %w", err)
+	}
+
+	width, height := img.Size()
+	bounds := image.Rect(0, 0, width, height)
+
+	sheet := &Sheet{Image: img, Frames: make([]Frame, 0, len(frames))}
+	for _, f := range frames {
+		if f.Rotated {
+			return nil, fmt.Errorf("frame %q is rotated, which isn't supported", f.Filename)
+		}
+
+		frame := Frame{
+			Name:     f.Filename,
+			Source:   image.Rect(f.Frame.X, f.Frame.Y, f.Frame.X+f.Frame.W, f.Frame.Y+f.Frame.H),
+			Size:     image.Point{X: f.Frame.W, Y: f.Frame.H},
+			Duration: time.Duration(f.Duration) * time.Millisecond,
+		}
+		if f.Trimmed {
+			frame.Offset = image.Point{X: f.SpriteSourceSize.X, Y: f.SpriteSourceSize.Y}
+			frame.Size = image.Point{X: f.SourceSize.W, Y: f.SourceSize.H}
+		}
+
+		if frame.Source.Empty() || !frame.Source.In(bounds) {
+			return nil, fmt.Errorf("frame %q is outside of the image", f.Filename)
+		}
+		sheet.Frames = append(sheet.Frames, frame)
+	}
+
+	for _, t := range description.Meta.FrameTags {
+		if t.From < 0 || t.To >= len(sheet.Frames) || t.From > t.To {
+			return nil, fmt.Errorf("tag %q has invalid frames %d to %d", t.Name, t.From, t.To)
+		}
+		sheet.Tags = append(sheet.Tags, Tag{Name: t.Name, From: t.From, To: t.To, Direction: t.Direction})
+	}
+
+	return sheet, nil
+}
+
+// Reads the frames in the order they appear in the file, which is the order of the animations exported by Aseprite
+// even when they are a hash
+func parseFrames(data json.RawMessage) ([]frameJson, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("no frames")
+	}
+
+	if trimmed[0] == '[' {
+		var frames []frameJson
+		if err := json.Unmarshal(trimmed, &frames); err != nil {
+			return nil, err
+		}
+		return frames, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	if token, err := decoder.Token(); err != nil {
+		return nil, err
+	} else if token != json.Delim('{') {
+		return nil, errors.New("frames must be an object or an array")
+	}
+
+	var frames []frameJson
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		var frame frameJson
+		if err := decoder.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = token.(string)
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
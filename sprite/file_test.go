@@ -0,0 +1,110 @@
+package sprite
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSheet(t *testing.T) {
+	img := headless.NewImage(image.NewRGBA(image.Rect(0, 0, 64, 32)))
+
+	tests := []struct {
+		name   string
+		json   string
+		frames []Frame
+		tags   []Tag
+	}{
+		{
+			name: "TexturePacker hash",
+			json: `{"frames": {
+				"walk_1": {"frame": {"x": 32, "y": 0, "w": 16, "h": 16}},
+				"walk_0": {"frame": {"x": 0, "y": 0, "w": 16, "h": 16}}
+			}, "meta": {"image": "sheet.png"}}`,
+			frames: []Frame{
+				{Name: "walk_1", Source: image.Rect(32, 0, 48, 16), Size: image.Pt(16, 16)},
+				{Name: "walk_0", Source: image.Rect(0, 0, 16, 16), Size: image.Pt(16, 16)},
+			},
+		},
+		{
+			name: "TexturePacker array with trimmed frame",
+			json: `{"frames": [
+				{"filename": "idle", "frame": {"x": 0, "y": 16, "w": 10, "h": 12}, "trimmed": true,
+					"spriteSourceSize": {"x": 3, "y": 4, "w": 10, "h": 12}, "sourceSize": {"w": 16, "h": 16}}
+			]}`,
+			frames: []Frame{
+				{Name: "idle", Source: image.Rect(0, 16, 10, 28), Offset: image.Pt(3, 4), Size: image.Pt(16, 16)},
+			},
+		},
+		{
+			name: "Aseprite with durations and tags",
+			json: `{"frames": {
+				"hero 0.aseprite": {"frame": {"x": 0, "y": 0, "w": 32, "h": 32}, "duration": 100},
+				"hero 1.aseprite": {"frame": {"x": 32, "y": 0, "w": 32, "h": 32}, "duration": 150}
+			}, "meta": {"frameTags": [{"name": "run", "from": 0, "to": 1, "direction": "pingpong"}]}}`,
+			frames: []Frame{
+				{Name: "hero 0.aseprite", Source: image.Rect(0, 0, 32, 32), Size: image.Pt(32, 32),
+					Duration: 100 * time.Millisecond},
+				{Name: "hero 1.aseprite", Source: image.Rect(32, 0, 64, 32), Size: image.Pt(32, 32),
+					Duration: 150 * time.Millisecond},
+			},
+			tags: []Tag{{Name: "run", From: 0, To: 1, Direction: "pingpong"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sheet, err := ParseSheet(strings.NewReader(test.json), img)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(sheet.Frames) != len(test.frames) {
+				t.Fatalf("got %d frames, want %d", len(sheet.Frames), len(test.frames))
+			}
+			for i, frame := range sheet.Frames {
+				if frame != test.frames[i] {
+					t.Errorf("frame %d = %+v, want %+v", i, frame, test.frames[i])
+				}
+			}
+
+			if len(sheet.Tags) != len(test.tags) {
+				t.Fatalf("got %d tags, want %d", len(sheet.Tags), len(test.tags))
+			}
+			for i, tag := range sheet.Tags {
+				if tag != test.tags[i] {
+					t.Errorf("tag %d = %+v, want %+v", i, tag, test.tags[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSheetErrors(t *testing.T) {
+	img := headless.NewImage(image.NewRGBA(image.Rect(0, 0, 32, 32)))
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"invalid JSON", `{"frames": `},
+		{"no frames", `{"meta": {}}`},
+		{"frames of the wrong type", `{"frames": 3}`},
+		{"rotated frame",
+			`{"frames": [{"filename": "a", "frame": {"x": 0, "y": 0, "w": 8, "h": 8}, "rotated": true}]}`},
+		{"frame outside of the image", `{"frames": [{"filename": "a", "frame": {"x": 30, "y": 0, "w": 8, "h": 8}}]}`},
+		{"empty frame", `{"frames": [{"filename": "a", "frame": {"x": 0, "y": 0, "w": 0, "h": 8}}]}`},
+		{"tag past the last frame", `{"frames": [{"filename": "a", "frame": {"x": 0, "y": 0, "w": 8, "h": 8}}],
+			"meta": {"frameTags": [{"name": "t", "from": 0, "to": 1}]}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseSheet(strings.NewReader(test.json), img); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
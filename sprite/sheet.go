@@ -0,0 +1,121 @@
+package sprite
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"time"
+)
+
+// Frame is a region of a sprite sheet's image, drawn as if it were a separate image.
+type Frame struct {
+	Name string
+	//Region of the image, with the origin at its top left corner
+	Source image.Rectangle
+	//Position of the region inside the original frame, for sheets that trim the transparent borders of their frames
+	Offset image.Point
+	//Size of the original frame, before being trimmed
+	Size image.Point
+	//How long the frame is shown in an animation, if the sheet defines it (Aseprite does)
+	Duration time.Duration
+}
+
+// Trimmed returns whether the frame is smaller than the original image it was made from.
+func (f Frame) Trimmed() bool {
+	return f.Offset != image.Point{} || f.Size != f.Source.Size()
+}
+
+// Tag is a named range of frames, like the animations exported by Aseprite.
+type Tag struct {
+	Name string
+	//Indices of the first and the last frames, inclusive
+	From int
+	To   int
+	//"forward", "reverse" or "pingpong", as exported by Aseprite; empty if the sheet doesn't define it
+	Direction string
+}
+
+// Sheet slices a single image into frames, so many sprites can be stored (and loaded) as one file.
+type Sheet struct {
+	Image  ui.Image
+	Frames []Frame
+	Tags   []Tag
+}
+
+// Grid describes a sheet where every frame has the same size, read from left to right and top to bottom.
+type Grid struct {
+	FrameWidth  int
+	FrameHeight int
+	//Space around the whole grid
+	Margin int
+	//Space between adjacent frames
+	Spacing int
+}
+
+// NewGridSheet slices the image into as many frames of the grid as it fits.
+func NewGridSheet(img ui.Image, grid Grid) *Sheet {
+	sheet := &Sheet{Image: img}
+	if grid.FrameWidth <= 0 || grid.FrameHeight <= 0 {
+		return sheet
+	}
+
+	width, height := img.Size()
+	size := image.Point{X: grid.FrameWidth, Y: grid.FrameHeight}
+	for y := grid.Margin; y+grid.FrameHeight <= height-grid.Margin; y += grid.FrameHeight + grid.Spacing {
+		for x := grid.Margin; x+grid.FrameWidth <= width-grid.Margin; x += grid.FrameWidth + grid.Spacing {
+			source := image.Rectangle{Min: image.Point{X: x, Y: y}, Max: image.Point{X: x, Y: y}.Add(size)}
+			sheet.Frames = append(sheet.Frames, Frame{Source: source, Size: size})
+		}
+	}
+	return sheet
+}
+
+// Frame returns the index of the frame with the given name.
+func (s *Sheet) Frame(name string) (int, bool) {
+	for i, frame := range s.Frames {
+		if frame.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// Tag returns the tag with the given name.
+func (s *Sheet) Tag(name string) (Tag, bool) {
+	for _, tag := range s.Tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// Unload releases the sheet's image, which was loaded by LoadSheet with the same loader.
+func (s *Sheet) Unload(imageLoader ui.ImageLoader) {
+	imageLoader.UnloadImage(s.Image)
+}
+
+// Draw draws a frame with the bottom left corner of its original image at the given position.
+func (s *Sheet) Draw(graphics ui.Graphics, index int, x int, y int) {
+	s.DrawOptions(graphics, index, ui.NewDrawOptions(float32(x), float32(y)))
+}
+
+// DrawOptions draws a frame transformed by the options, which are relative to its original image (e.g. the origin
+// doesn't change when the frame is trimmed).
+func (s *Sheet) DrawOptions(graphics ui.Graphics, index int, options ui.DrawOptions) {
+	frame := s.Frames[index]
+	options.Source = frame.Source
+
+	//The region is drawn from its own bottom left corner, which is somewhere inside the original image
+	left := frame.Offset.X
+	bottom := frame.Size.Y - frame.Offset.Y - frame.Source.Dy()
+	if options.FlipX {
+		left = frame.Size.X - frame.Offset.X - frame.Source.Dx()
+	}
+	if options.FlipY {
+		bottom = frame.Offset.Y
+	}
+	options.OriginX -= float32(left)
+	options.OriginY -= float32(bottom)
+
+	graphics.DrawImageOptions(s.Image, options)
+}
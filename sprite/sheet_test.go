@@ -0,0 +1,51 @@
+package sprite
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui/headless"
+	"image"
+	"testing"
+)
+
+func TestNewGridSheet(t *testing.T) {
+	tests := []struct {
+		name    string
+		width   int
+		height  int
+		grid    Grid
+		sources []image.Rectangle
+	}{
+		{
+			name: "exact fit", width: 32, height: 16, grid: Grid{FrameWidth: 16, FrameHeight: 16},
+			sources: []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(16, 0, 32, 16)},
+		},
+		{
+			name: "margin and spacing", width: 23, height: 23,
+			grid: Grid{FrameWidth: 10, FrameHeight: 10, Margin: 1, Spacing: 1},
+			sources: []image.Rectangle{
+				image.Rect(1, 1, 11, 11), image.Rect(12, 1, 22, 11),
+				image.Rect(1, 12, 11, 22), image.Rect(12, 12, 22, 22),
+			},
+		},
+		{
+			name: "partial frames left out", width: 40, height: 20, grid: Grid{FrameWidth: 16, FrameHeight: 16},
+			sources: []image.Rectangle{image.Rect(0, 0, 16, 16), image.Rect(16, 0, 32, 16)},
+		},
+		{name: "invalid frame size", width: 16, height: 16, grid: Grid{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img := headless.NewImage(image.NewRGBA(image.Rect(0, 0, test.width, test.height)))
+			sheet := NewGridSheet(img, test.grid)
+
+			if len(sheet.Frames) != len(test.sources) {
+				t.Fatalf("got %d frames, want %d", len(sheet.Frames), len(test.sources))
+			}
+			for i, frame := range sheet.Frames {
+				if frame.Source != test.sources[i] || frame.Trimmed() {
+					t.Errorf("frame %d = %+v, want source %v untrimmed", i, frame, test.sources[i])
+				}
+			}
+		})
+	}
+}
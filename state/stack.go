@@ -0,0 +1,59 @@
+package state
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"time"
+)
+
+// PushRequest is returned by Update (through Push) to place a child state on top of the current one. The current state
+// stays loaded and is drawn below the child, which receives the input until it is popped.
+type PushRequest struct {
+	request
+	Child State
+}
+
+// PopRequest is returned by Update (through Pop) to unload the current state and resume the one below it. Popping the
+// last state ends the game, like returning nil.
+type PopRequest struct {
+	request
+}
+
+func Push(child State) State {
+	return &PushRequest{Child: child}
+}
+
+func Pop() State {
+	return &PopRequest{}
+}
+
+// Background can be implemented by states that keep updating while another state is on top of them (e.g. a game world
+// that keeps running behind a dialog). The stack can only be changed by the state on top, so nothing is returned.
+type Background interface {
+	BackgroundUpdate(delta time.Duration)
+}
+
+// Pausable can be implemented by states that need to know when another state is pushed on top of them and when they
+// become the top state again.
+type Pausable interface {
+	Pause()
+	Resume()
+}
+
+// Base for the values returned by Update that change the stack instead of being actual states. They are never loaded,
+// updated or drawn by the game.
+type request struct {
+}
+
+func (request) Load(ui.ImageLoader) error {
+	return nil
+}
+
+func (request) Update(time.Duration) State {
+	return nil
+}
+
+func (request) Draw(ui.Graphics) {
+}
+
+func (request) Unload(ui.ImageLoader) {
+}
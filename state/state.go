@@ -1,13 +1,54 @@
 package state
 
 import (
+	"github.com/Hikarikun92/go-game-engine/input"
 	"github.com/Hikarikun92/go-game-engine/ui"
 	"time"
 )
 
 type State interface {
-	Load(imageLoader ui.ImageLoader)
+	// Load prepares the state to be used. If it returns an error, it must release whatever it already loaded.
+	Load(imageLoader ui.ImageLoader) error
+	// Update returns the state itself to keep running, another state to replace it, a request created by Push, Pop or
+	// WithTransition, or nil to end the game. The returned state is compared with the current one using ==, so the
+	// states are usually pointers.
 	Update(delta time.Duration) State
 	Draw(graphics ui.Graphics)
 	Unload(imageLoader ui.ImageLoader)
 }
+
+// Interpolated can be implemented by states that want to smooth their rendering when the game runs with a fixed update
+// step. Instead of Draw, the game calls DrawInterpolated with alpha in the range [0, 1) representing how far the current
+// frame is between the last update and the next one.
+type Interpolated interface {
+	DrawInterpolated(graphics ui.Graphics, alpha float64)
+}
+
+// LoadErrorHandler can be implemented by states to decide what happens when the state they returned from Update (to
+// replace, push or transition to) fails to load. The returned value is handled like the return value of Update: the
+// handler can return itself to keep running, another state as a fallback, or nil to end the game. If the state returned
+// by the handler fails to load as well, the game ends. States that don't implement it end the game when that happens.
+type LoadErrorHandler interface {
+	LoadFailed(failed State, err error) State
+}
+
+// Preloader can be implemented by states with many or large images. Before the state is loaded, the game decodes the
+// files returned by Assets in the background, showing the loading screen (if any) in the meantime; then, calls to
+// LoadImage for those files in Load return immediately. The initial state of the game is always loaded directly.
+type Preloader interface {
+	Assets() []string
+	LoadingScreen() LoadingScreen
+}
+
+// LoadingScreen is a lightweight state shown while the assets of a Preloader are decoded. It is drawn over the current
+// states and receives the progress every frame; the value returned by its Update is ignored.
+type LoadingScreen interface {
+	State
+	Progress(done int, total int)
+}
+
+// InputUser can be implemented by states that query the input during Update instead of (or besides) receiving the
+// input events. The game gives them the snapshot before loading them.
+type InputUser interface {
+	UseInput(snapshot *input.Snapshot)
+}
@@ -0,0 +1,28 @@
+package state
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"time"
+)
+
+// Transition animates the replacement of a state by another. While it runs, both states stay loaded and neither of them
+// is updated; the outgoing state is only unloaded when the transition finishes.
+type Transition interface {
+	Duration() time.Duration
+
+	// Draw renders the transition on a screen of the given size. Progress goes from 0 (only the outgoing state) to 1
+	// (only the incoming state), and the states are drawn by calling drawFrom and drawTo.
+	Draw(graphics ui.Graphics, width int, height int, progress float64, drawFrom func(), drawTo func())
+}
+
+// TransitionRequest is returned by Update (through WithTransition) to replace the current state using a transition
+// instead of a hard cut.
+type TransitionRequest struct {
+	request
+	Next       State
+	Transition Transition
+}
+
+func WithTransition(next State, transition Transition) State {
+	return &TransitionRequest{Next: next, Transition: transition}
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image/color"
+	"time"
+)
+
+const (
+	barWidth      = 2
+	pixelsPerMs   = 4
+	overlayMargin = 4
+)
+
+var (
+	backgroundColor = color.RGBA{R: 0, G: 0, B: 0, A: 160}
+	updateColor     = color.RGBA{R: 80, G: 200, B: 80, A: 255}
+	drawColor       = color.RGBA{R: 80, G: 140, B: 255, A: 255}
+	swapColor       = color.RGBA{R: 230, G: 80, B: 80, A: 255}
+	droppedColor    = color.RGBA{R: 255, G: 0, B: 255, A: 255}
+	budgetColor     = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// DrawOverlay draws a graph of the frame times in the top left corner of the screen: one bar per frame, stacking the
+// update (green), draw (blue) and swap (red) times, with a magenta mark on frames that dropped ticks. The white line is
+// the time budget of a single frame.
+func DrawOverlay(graphics ui.Graphics, history []Frame, budget time.Duration, screenHeight int) {
+	budgetHeight := durationHeight(budget)
+	width := historySize * barWidth
+	height := budgetHeight * 2
+
+	left := overlayMargin
+	bottom := screenHeight - overlayMargin - height
+
+	graphics.FillRectangle(left, bottom, width, height, backgroundColor)
+	graphics.SetClip(left, bottom, width, height)
+
+	for i, frame := range history {
+		x := left + i*barWidth
+		y := bottom
+
+		for _, part := range []struct {
+			duration time.Duration
+			color    color.Color
+		}{{frame.Update, updateColor}, {frame.Draw, drawColor}, {frame.Swap, swapColor}} {
+			partHeight := durationHeight(part.duration)
+			graphics.FillRectangle(x, y, barWidth, partHeight, part.color)
+			y += partHeight
+		}
+
+		if frame.DroppedTicks > 0 {
+			graphics.FillRectangle(x, bottom+height-barWidth, barWidth, barWidth, droppedColor)
+		}
+	}
+
+	graphics.FillRectangle(left, bottom+budgetHeight, width, 1, budgetColor)
+	graphics.ClearClip()
+}
+
+func durationHeight(duration time.Duration) int {
+	return int(duration * pixelsPerMs / time.Millisecond)
+}
@@ -0,0 +1,111 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// Amount of frames kept in the history
+const historySize = 120
+
+// Frame holds the measurements of a single frame.
+type Frame struct {
+	//When the frame started
+	Start time.Time
+
+	//Time spent updating the states
+	Update time.Duration
+	//Time spent drawing the states
+	Draw time.Duration
+	//Time spent presenting the frame and polling the window's events
+	Swap time.Duration
+
+	//Images and rectangles drawn by the states
+	DrawCalls int
+	//Batches sent to the GPU by backends that group the draws, which is the actual amount of draw calls
+	Batches int
+	//Ticks of the frame timer that were missed since the previous frame
+	DroppedTicks int
+}
+
+// Stats summarizes the frames measured so far.
+type Stats struct {
+	Last Frame
+	//Frames per second actually achieved, over the frames in the history
+	Fps          float64
+	Frames       int
+	DroppedTicks int
+}
+
+// Collector keeps the measurements of the last frames. It can be read from any goroutine.
+type Collector struct {
+	mutex        sync.Mutex
+	history      [historySize]Frame
+	next         int
+	frames       int
+	droppedTicks int
+}
+
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+func (c *Collector) Add(frame Frame) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.history[c.next] = frame
+	c.next = (c.next + 1) % historySize
+	c.frames++
+	c.droppedTicks += frame.DroppedTicks
+}
+
+func (c *Collector) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	history := c.historyLocked()
+	if len(history) == 0 {
+		return Stats{}
+	}
+
+	stats := Stats{Last: history[len(history)-1], Frames: c.frames, DroppedTicks: c.droppedTicks}
+
+	elapsed := history[len(history)-1].Start.Sub(history[0].Start)
+	if elapsed > 0 {
+		stats.Fps = float64(len(history)-1) / elapsed.Seconds()
+	}
+	return stats
+}
+
+// History returns the last frames, from the oldest to the newest.
+func (c *Collector) History() []Frame {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.historyLocked()
+}
+
+func (c *Collector) historyLocked() []Frame {
+	if c.frames < historySize {
+		return append([]Frame(nil), c.history[:c.next]...)
+	}
+
+	history := make([]Frame, 0, historySize)
+	history = append(history, c.history[c.next:]...)
+	return append(history, c.history[:c.next]...)
+}
+
+// DroppedTicks calculates how many ticks of a timer with the given period were missed, given the time elapsed since
+// the last tick that was received.
+func DroppedTicks(elapsed time.Duration, period time.Duration) int {
+	if period <= 0 {
+		return 0
+	}
+
+	dropped := int((elapsed+period/2)/period) - 1
+	if dropped < 0 {
+		return 0
+	}
+	return dropped
+}
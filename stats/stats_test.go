@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDroppedTicks(t *testing.T) {
+	const period = 10 * time.Millisecond
+
+	tests := []struct {
+		elapsed time.Duration
+		period  time.Duration
+		want    int
+	}{
+		{10 * time.Millisecond, period, 0},
+		{14 * time.Millisecond, period, 0},
+		{15 * time.Millisecond, period, 1},
+		{30 * time.Millisecond, period, 2},
+		{time.Second, period, 99},
+		{0, period, 0},
+		{time.Second, 0, 0},
+	}
+
+	for _, test := range tests {
+		if got := DroppedTicks(test.elapsed, test.period); got != test.want {
+			t.Errorf("DroppedTicks(%v, %v) = %d, want %d", test.elapsed, test.period, got, test.want)
+		}
+	}
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	if stats := c.Stats(); stats != (Stats{}) {
+		t.Errorf("stats without frames = %+v, want zero", stats)
+	}
+
+	start := time.Now()
+	const frames = historySize + 10
+	for i := 0; i < frames; i++ {
+		c.Add(Frame{Start: start.Add(time.Duration(i) * 10 * time.Millisecond), DroppedTicks: i % 2, DrawCalls: i})
+	}
+
+	history := c.History()
+	if len(history) != historySize || history[0].DrawCalls != 10 || history[historySize-1].DrawCalls != frames-1 {
+		t.Fatalf("history has %d frames from %d to %d, want the last %d", len(history), history[0].DrawCalls,
+			history[len(history)-1].DrawCalls, historySize)
+	}
+
+	stats := c.Stats()
+	if stats.Frames != frames || stats.DroppedTicks != frames/2 || stats.Last.DrawCalls != frames-1 {
+		t.Errorf("got %+v, want %d frames with %d dropped ticks", stats, frames, frames/2)
+	}
+	if stats.Fps < 99.9 || stats.Fps > 100.1 {
+		t.Errorf("fps = %v, want 100", stats.Fps)
+	}
+}
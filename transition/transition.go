@@ -0,0 +1,149 @@
+package transition
+
+import (
+	"github.com/Hikarikun92/go-game-engine/state"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image/color"
+	"time"
+)
+
+type Direction byte
+
+// Direction in which the incoming state moves into the screen
+const (
+	LEFT  Direction = 0
+	RIGHT Direction = 1
+	UP    Direction = 2
+	DOWN  Direction = 3
+)
+
+type fadeImpl struct {
+	duration time.Duration
+	color    color.Color
+}
+
+// Fade covers the outgoing state with a solid color during the first half of the transition, then uncovers the
+// incoming state during the second half.
+func Fade(duration time.Duration, c color.Color) state.Transition {
+	return &fadeImpl{duration: duration, color: c}
+}
+
+func (f *fadeImpl) Duration() time.Duration {
+	return f.duration
+}
+
+func (f *fadeImpl) Draw(graphics ui.Graphics, width int, height int, progress float64, drawFrom func(), drawTo func()) {
+	var coverage float64
+	if progress < 0.5 {
+		drawFrom()
+		coverage = progress * 2
+	} else {
+		drawTo()
+		coverage = (1 - progress) * 2
+	}
+
+	graphics.SetOpacity(float32(coverage))
+	graphics.FillRectangle(0, 0, width, height, f.color)
+	graphics.SetOpacity(1.0)
+}
+
+type crossfadeImpl struct {
+	duration time.Duration
+}
+
+// Crossfade draws the incoming state over the outgoing one, increasingly opaque.
+func Crossfade(duration time.Duration) state.Transition {
+	return &crossfadeImpl{duration: duration}
+}
+
+func (c *crossfadeImpl) Duration() time.Duration {
+	return c.duration
+}
+
+func (c *crossfadeImpl) Draw(graphics ui.Graphics, width int, height int, progress float64, drawFrom func(), drawTo func()) {
+	drawFrom()
+
+	graphics.SetOpacity(float32(progress))
+	drawTo()
+	graphics.SetOpacity(1.0)
+}
+
+type slideImpl struct {
+	duration  time.Duration
+	direction Direction
+}
+
+// Slide moves the incoming state into the screen in the given direction, pushing the outgoing state out of it.
+func Slide(duration time.Duration, direction Direction) state.Transition {
+	return &slideImpl{duration: duration, direction: direction}
+}
+
+func (s *slideImpl) Duration() time.Duration {
+	return s.duration
+}
+
+func (s *slideImpl) Draw(graphics ui.Graphics, width int, height int, progress float64, drawFrom func(), drawTo func()) {
+	//Distance the outgoing state has moved so far; the incoming state is always one screen behind it
+	dx, dy := directionVector(s.direction)
+	distanceX := int(float64(dx*width) * progress)
+	distanceY := int(float64(dy*height) * progress)
+
+	graphics.SetOffset(distanceX, distanceY)
+	drawFrom()
+
+	graphics.SetOffset(distanceX-dx*width, distanceY-dy*height)
+	drawTo()
+
+	graphics.SetOffset(0, 0)
+}
+
+type wipeImpl struct {
+	duration  time.Duration
+	direction Direction
+}
+
+// Wipe reveals the incoming state over the outgoing one, with the edge moving in the given direction.
+func Wipe(duration time.Duration, direction Direction) state.Transition {
+	return &wipeImpl{duration: duration, direction: direction}
+}
+
+func (w *wipeImpl) Duration() time.Duration {
+	return w.duration
+}
+
+func (w *wipeImpl) Draw(graphics ui.Graphics, width int, height int, progress float64, drawFrom func(), drawTo func()) {
+	drawFrom()
+
+	revealedWidth := int(float64(width) * progress)
+	revealedHeight := int(float64(height) * progress)
+
+	switch w.direction {
+	case LEFT:
+		graphics.SetClip(width-revealedWidth, 0, revealedWidth, height)
+	case RIGHT:
+		graphics.SetClip(0, 0, revealedWidth, height)
+	case UP:
+		graphics.SetClip(0, 0, width, revealedHeight)
+	case DOWN:
+		graphics.SetClip(0, height-revealedHeight, width, revealedHeight)
+	}
+	drawTo()
+
+	graphics.ClearClip()
+}
+
+// Unit vector of a direction, with the Y axis pointing up like the screen coordinates
+func directionVector(direction Direction) (int, int) {
+	switch direction {
+	case LEFT:
+		return -1, 0
+	case RIGHT:
+		return 1, 0
+	case UP:
+		return 0, 1
+	case DOWN:
+		return 0, -1
+	default:
+		return 0, 0
+	}
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"fmt"
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// DecodeImage reads an image file in any of the supported formats. It doesn't depend on the window, so it can be called
+// from any goroutine.
+func DecodeImage(file string) (image.Image, error) {
+	imgFile, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("texture %q not found on disk: %w", file, err)
+	}
+	defer imgFile.Close()
+
+	//Decode the image to a know structure (using the imports with _)
+	img, _, err := image.Decode(imgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode texture %q: %w", file, err)
+	}
+
+	return img, nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+)
+
+type fallbackImageLoader struct {
+	ImageLoader
+	onError func(file string, err error)
+
+	missingImage Image
+	//How many times the placeholder was returned and not unloaded yet, so it can be unloaded when nothing uses it
+	missingImageUses int
+}
+
+// NewFallbackImageLoader wraps an ImageLoader so that images that fail to load are replaced by a placeholder (a
+// checkerboard, like in many other engines) instead of returning an error. Each failure is reported to onError.
+func NewFallbackImageLoader(imageLoader ImageLoader, onError func(file string, err error)) ImageLoader {
+	return &fallbackImageLoader{ImageLoader: imageLoader, onError: onError}
+}
+
+// MissingImageReplacer is implemented by the ImageLoader returned by NewFallbackImageLoader, so the images that failed
+// to load somewhere else (e.g. decoded in the background) can be replaced by the same placeholder. The placeholder is
+// unloaded like any other image.
+type MissingImageReplacer interface {
+	ReplaceMissingImage(file string, err error) (Image, error)
+}
+
+func (f *fallbackImageLoader) LoadImage(file string) (Image, error) {
+	img, err := f.ImageLoader.LoadImage(file)
+	if err == nil {
+		return img, nil
+	}
+	return f.ReplaceMissingImage(file, err)
+}
+
+func (f *fallbackImageLoader) ReplaceMissingImage(file string, err error) (Image, error) {
+	f.onError(file, err)
+
+	//The placeholder is only created when it is needed, and then shared by all the missing images
+	if f.missingImage == nil {
+		missingImage, err := f.ImageLoader.CreateImage(newMissingImage())
+		if err != nil {
+			return nil, err
+		}
+		f.missingImage = missingImage
+	}
+	f.missingImageUses++
+	return f.missingImage, nil
+}
+
+func (f *fallbackImageLoader) UnloadImage(image Image) {
+	if image != f.missingImage || f.missingImage == nil {
+		f.ImageLoader.UnloadImage(image)
+		return
+	}
+
+	//The placeholder may still be used by other states
+	f.missingImageUses--
+	if f.missingImageUses <= 0 {
+		f.ImageLoader.UnloadImage(f.missingImage)
+		f.missingImage = nil
+		f.missingImageUses = 0
+	}
+}
+
+// A 16x16 magenta and black checkerboard
+func newMissingImage() image.Image {
+	const size = 16
+	const squareSize = 4
+
+	magenta := color.RGBA{R: 255, G: 0, B: 255, A: 255}
+	black := color.RGBA{R: 0, G: 0, B: 0, A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if (x/squareSize+y/squareSize)%2 == 0 {
+				img.SetRGBA(x, y, magenta)
+			} else {
+				img.SetRGBA(x, y, black)
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"errors"
+	"image"
+	"testing"
+)
+
+type fakeImage struct {
+	width  int
+	height int
+}
+
+func (i *fakeImage) Size() (int, int) {
+	return i.width, i.height
+}
+
+// Fails to load every file and counts the images that are still loaded
+type failingLoader struct {
+	loaded int
+}
+
+func (l *failingLoader) LoadImage(file string) (Image, error) {
+	return nil, errors.New("not found")
+}
+
+func (l *failingLoader) CreateImage(img image.Image) (Image, error) {
+	l.loaded++
+	bounds := img.Bounds()
+	return &fakeImage{width: bounds.Dx(), height: bounds.Dy()}, nil
+}
+
+func (l *failingLoader) CreateSubImage(parent Image, region image.Rectangle) (Image, error) {
+	return parent, nil
+}
+
+func (l *failingLoader) UnloadImage(image Image) {
+	l.loaded--
+}
+
+func TestFallbackImageLoaderSharesPlaceholder(t *testing.T) {
+	loader := &failingLoader{}
+	var failures []string
+	fallback := NewFallbackImageLoader(loader, func(file string, err error) {
+		failures = append(failures, file)
+	})
+
+	a, err := fallback.LoadImage("a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := fallback.(MissingImageReplacer).ReplaceMissingImage("b.png", errors.New("corrupted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b || loader.loaded != 1 {
+		t.Fatalf("%d placeholders were created, want a single shared one", loader.loaded)
+	}
+	if len(failures) != 2 {
+		t.Errorf("reported failures %v, want a.png and b.png", failures)
+	}
+
+	fallback.UnloadImage(a)
+	if loader.loaded != 1 {
+		t.Fatal("the placeholder was unloaded while still in use")
+	}
+	fallback.UnloadImage(b)
+	if loader.loaded != 0 {
+		t.Fatal("the placeholder wasn't unloaded after its last use")
+	}
+
+	//A new placeholder is created for the next failure
+	if _, err := fallback.LoadImage("c.png"); err != nil {
+		t.Fatal(err)
+	}
+	if loader.loaded != 1 {
+		t.Errorf("%d images are loaded, want 1", loader.loaded)
+	}
+}
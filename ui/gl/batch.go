@@ -0,0 +1,134 @@
+package gl
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Maximum amount of quads sent in a single draw call; more quads with the same texture are split into several batches
+const maxBatchQuads = 4096
+
+// Each vertex has its position (X and Y), its texture coordinates (U and V), the bounds of the texture coordinates that
+// can be sampled (minimum U and V, maximum U and V) and its tint (red, green, blue and alpha)
+const vertexFloats = 12
+
+// Corners of the unit square, in the order of the vertices of each quad: top left, bottom right, bottom left and top
+// right. The texture coordinates have the origin at the top left corner, so they are the same corners with Y inverted.
+var unitSquare = [4]mgl32.Vec2{{0, 1}, {1, 0}, {0, 0}, {1, 1}}
+
+// Accumulates quads that use the same texture and blending in a dynamic vertex buffer, drawing all of them with a
+// single draw call when something changes or the frame ends
+type batch struct {
+	vertexArrayObject   uint32
+	vertexBufferObject  uint32
+	elementBufferObject uint32
+
+	vertices []float32
+	texture  uint32
+	blend    bool
+
+	//Draw calls issued since the counter was reset
+	flushes int
+}
+
+func newBatch() *batch {
+	//The indices never change: each quad is made of 2 triangles, sharing the diagonal from top left to bottom right
+	indices := make([]uint32, 0, maxBatchQuads*6)
+	for quad := uint32(0); quad < maxBatchQuads; quad++ {
+		first := quad * 4
+		indices = append(indices, first, first+1, first+2, first, first+3, first+1)
+	}
+
+	b := &batch{vertices: make([]float32, 0, maxBatchQuads*4*vertexFloats)}
+	gl.GenVertexArrays(1, &b.vertexArrayObject)
+	gl.GenBuffers(1, &b.vertexBufferObject)
+	gl.GenBuffers(1, &b.elementBufferObject)
+
+	//Work on this specific object
+	gl.BindVertexArray(b.vertexArrayObject)
+
+	//Reserve the memory of the vertices, which are uploaded on every flush
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vertexBufferObject)
+	gl.BufferData(gl.ARRAY_BUFFER, cap(b.vertices)*4, nil, gl.DYNAMIC_DRAW)
+
+	//Load the indices into memory
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, b.elementBufferObject)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+
+	//Position, texture coordinates, sampling bounds and tint attributes (the "location = 0, 1, 2, 3" in the shader)
+	stride := int32(vertexFloats * 4)
+	gl.VertexAttribPointerWithOffset(0, 2, gl.FLOAT, false, stride, 0)
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointerWithOffset(1, 2, gl.FLOAT, false, stride, 2*4)
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointerWithOffset(2, 4, gl.FLOAT, false, stride, 4*4)
+	gl.EnableVertexAttribArray(2)
+	gl.VertexAttribPointerWithOffset(3, 4, gl.FLOAT, false, stride, 8*4)
+	gl.EnableVertexAttribArray(3)
+
+	return b
+}
+
+// Queues a quad, flushing the previous ones first if they can't be drawn together with it. The positions and texture
+// coordinates are in the order of the unit square.
+func (b *batch) add(texture uint32, blend bool, positions [4]mgl32.Vec2, texCoords [4]mgl32.Vec2, bounds mgl32.Vec4,
+	tint mgl32.Vec4) {
+	if len(b.vertices) > 0 && (texture != b.texture || blend != b.blend) {
+		b.flush()
+	}
+	if len(b.vertices) == cap(b.vertices) {
+		b.flush()
+	}
+
+	b.texture = texture
+	b.blend = blend
+	for i := range positions {
+		b.vertices = append(b.vertices,
+			positions[i].X(), positions[i].Y(),
+			texCoords[i].X(), texCoords[i].Y(),
+			bounds.X(), bounds.Y(), bounds.Z(), bounds.W(),
+			tint.X(), tint.Y(), tint.Z(), tint.W())
+	}
+}
+
+// Draws the queued quads, if any
+func (b *batch) flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+
+	if b.blend {
+		gl.Enable(gl.BLEND)
+	} else {
+		gl.Disable(gl.BLEND)
+	}
+
+	gl.ActiveTexture(gl.TEXTURE0)
+	gl.BindTexture(gl.TEXTURE_2D, b.texture)
+
+	//Orphan the previous buffer so the driver doesn't have to wait until the GPU is done with it
+	gl.BindVertexArray(b.vertexArrayObject)
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.vertexBufferObject)
+	gl.BufferData(gl.ARRAY_BUFFER, cap(b.vertices)*4, nil, gl.DYNAMIC_DRAW)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(b.vertices)*4, gl.Ptr(b.vertices))
+
+	quads := len(b.vertices) / (4 * vertexFloats)
+	gl.DrawElements(gl.TRIANGLES, int32(quads*6), gl.UNSIGNED_INT, nil)
+
+	b.vertices = b.vertices[:0]
+	b.flushes++
+}
+
+// Returns how many draw calls were issued since the counter was reset, including the one of the quads still queued
+func (b *batch) batches() int {
+	if len(b.vertices) > 0 {
+		return b.flushes + 1
+	}
+	return b.flushes
+}
+
+func (b *batch) delete() {
+	gl.DeleteVertexArrays(1, &b.vertexArrayObject)
+	gl.DeleteBuffers(1, &b.vertexBufferObject)
+	gl.DeleteBuffers(1, &b.elementBufferObject)
+}
@@ -0,0 +1,28 @@
+package gl
+
+import (
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Adapter for GLFW's mouse buttons and the engine's buttons
+func translateButton(glfwButton glfw.MouseButton) cursor.Button {
+	switch glfwButton {
+	case glfw.MouseButtonLeft:
+		return cursor.LEFT
+	case glfw.MouseButtonRight:
+		return cursor.RIGHT
+	case glfw.MouseButtonMiddle:
+		return cursor.MIDDLE
+	case glfw.MouseButton4:
+		return cursor.BUTTON_4
+	case glfw.MouseButton5:
+		return cursor.BUTTON_5
+	case glfw.MouseButton6:
+		return cursor.BUTTON_6
+	case glfw.MouseButton7:
+		return cursor.BUTTON_7
+	default:
+		return cursor.BUTTON_8
+	}
+}
@@ -0,0 +1,52 @@
+package gl
+
+import (
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+type gamepadPollerImpl struct {
+}
+
+func (p *gamepadPollerImpl) Poll() []gamepad.Gamepad {
+	var gamepads []gamepad.Gamepad
+
+	for joystick := glfw.Joystick1; joystick <= glfw.JoystickLast; joystick++ {
+		//Only the joysticks with a standard mapping are supported
+		if !joystick.Present() || !joystick.IsGamepad() {
+			continue
+		}
+
+		glfwState := joystick.GetGamepadState()
+		if glfwState == nil {
+			continue
+		}
+
+		gamepads = append(gamepads, gamepad.Gamepad{
+			ID:    gamepad.ID(joystick),
+			Name:  joystick.GetGamepadName(),
+			State: translateGamepadState(glfwState),
+		})
+	}
+
+	return gamepads
+}
+
+// Adapter for GLFW's gamepad state and the engine's state. The buttons and axes are in the same order, but GLFW's Y
+// axes point down and its triggers go from -1 to 1.
+func translateGamepadState(glfwState *glfw.GamepadState) gamepad.State {
+	var state gamepad.State
+
+	for button, action := range glfwState.Buttons {
+		state.Buttons[button] = action == glfw.Press
+	}
+
+	state.Axes[gamepad.LEFT_X] = glfwState.Axes[glfw.AxisLeftX]
+	state.Axes[gamepad.LEFT_Y] = -glfwState.Axes[glfw.AxisLeftY]
+	state.Axes[gamepad.RIGHT_X] = glfwState.Axes[glfw.AxisRightX]
+	state.Axes[gamepad.RIGHT_Y] = -glfwState.Axes[glfw.AxisRightY]
+	state.Axes[gamepad.LEFT_TRIGGER] = (glfwState.Axes[glfw.AxisLeftTrigger] + 1) / 2
+	state.Axes[gamepad.RIGHT_TRIGGER] = (glfwState.Axes[glfw.AxisRightTrigger] + 1) / 2
+
+	return state
+}
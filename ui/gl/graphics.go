@@ -4,23 +4,130 @@ import (
 	"github.com/Hikarikun92/go-game-engine/ui"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
+	"image/color"
 )
 
+// The whole texture, in texture coordinates
+var fullTexture = [4]float32{0.0, 0.0, 1.0, 1.0}
+
+// Sampling bounds that don't restrict anything, for the whole texture (whose edges are already clamped by OpenGL)
+var fullBounds = mgl32.Vec4{0.0, 0.0, 1.0, 1.0}
+
 type graphicsImpl struct {
-	shaderProgram uint32
+	batch        *batch
+	whiteTexture uint32
+	viewport     ui.Viewport
+
+	offsetX int
+	offsetY int
+	opacity float32
+
+	drawCalls int
 }
 
 func (g *graphicsImpl) DrawImage(image ui.Image, x int, y int) {
 	img := image.(imageImpl)
 
-	model := mgl32.Translate3D(float32(x), float32(y), 0)
-	model = model.Mul4(mgl32.Scale3D(img.width, img.height, 1.0))
+	left, bottom := float32(x+g.offsetX), float32(y+g.offsetY)
+	model := mgl32.Translate3D(left, bottom, 0).Mul4(mgl32.Scale3D(img.width, img.height, 1.0))
+	g.drawModel(img.textureId, model, img.region, img.sampleBounds(img.region), mgl32.Vec4{1.0, 1.0, 1.0, 1.0})
+}
+
+func (g *graphicsImpl) DrawImageOptions(image ui.Image, options ui.DrawOptions) {
+	img := image.(imageImpl)
+
+	tint := mgl32.Vec4{1.0, 1.0, 1.0, 1.0}
+	if options.Tint != nil {
+		tint = toTint(options.Tint)
+	}
+	tint[3] *= options.Opacity()
+
+	//Region of the texture drawn, in texture coordinates (from 0 to 1 with the origin at the top left corner)
+	source := options.SourceRectangle(img)
+	if source.Empty() {
+		return //Nothing of the image is inside the region
+	}
+	texture := img.subRegion(source)
+
+	//Applied from the last to the first: flip the unit square, make it the region's size, put the origin at (0, 0),
+	//scale and rotate around it and finally move it to the position
+	model := mgl32.Translate3D(options.X+float32(g.offsetX), options.Y+float32(g.offsetY), 0)
+	model = model.Mul4(mgl32.HomogRotate3DZ(options.Rotation))
+	scaleX, scaleY := options.Scale()
+	model = model.Mul4(mgl32.Scale3D(scaleX, scaleY, 1.0))
+	model = model.Mul4(mgl32.Translate3D(-options.OriginX, -options.OriginY, 0))
+	model = model.Mul4(mgl32.Scale3D(float32(source.Dx()), float32(source.Dy()), 1.0))
+	if options.FlipX {
+		model = model.Mul4(mgl32.Translate3D(1.0, 0, 0)).Mul4(mgl32.Scale3D(-1.0, 1.0, 1.0))
+	}
+	if options.FlipY {
+		model = model.Mul4(mgl32.Translate3D(0, 1.0, 0)).Mul4(mgl32.Scale3D(1.0, -1.0, 1.0))
+	}
+
+	g.drawModel(img.textureId, model, texture, img.sampleBounds(texture), tint)
+}
+
+func (g *graphicsImpl) FillRectangle(x int, y int, width int, height int, c color.Color) {
+	left, bottom := float32(x+g.offsetX), float32(y+g.offsetY)
+	model := mgl32.Translate3D(left, bottom, 0).Mul4(mgl32.Scale3D(float32(width), float32(height), 1.0))
+	g.drawModel(g.whiteTexture, model, fullTexture, fullBounds, toTint(c))
+}
+
+func (g *graphicsImpl) SetOffset(x int, y int) {
+	g.offsetX = x
+	g.offsetY = y
+}
+
+func (g *graphicsImpl) SetClip(x int, y int, width int, height int) {
+	//The queued quads must still be drawn with the previous clip
+	g.batch.flush()
+
+	//Both OpenGL and the engine use the bottom left corner as the origin, but the scissor works with the window's
+	//pixels instead of the logical resolution
+	x, y, width, height = g.viewport.ToWindow(x, y, width, height)
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(int32(x), int32(y), int32(width), int32(height))
+}
 
-	modelUniform := gl.GetUniformLocation(g.shaderProgram, gl.Str("model\x00"))
-	gl.UniformMatrix4fv(modelUniform, 1, false, &model[0])
+func (g *graphicsImpl) ClearClip() {
+	g.batch.flush()
+	gl.Disable(gl.SCISSOR_TEST)
+}
+
+func (g *graphicsImpl) SetOpacity(opacity float32) {
+	g.opacity = opacity
+}
 
-	gl.ActiveTexture(gl.TEXTURE0)
-	gl.BindTexture(gl.TEXTURE_2D, img.textureId)
+func (g *graphicsImpl) DrawCalls() int {
+	return g.drawCalls
+}
+
+func (g *graphicsImpl) Batches() int {
+	return g.batch.batches()
+}
+
+// Queues the unit square transformed by the model matrix, multiplying the colors of the texture's region by the tint.
+// The blending is only enabled if the result can be translucent.
+func (g *graphicsImpl) drawModel(textureId uint32, model mgl32.Mat4, texture [4]float32, bounds mgl32.Vec4,
+	tint mgl32.Vec4) {
+	tint[3] *= g.opacity
+	blend := tint[3] < 1.0
+
+	//The textures are premultiplied, so the tint must be too for the blending to apply its alpha only once
+	tint = mgl32.Vec4{tint[0] * tint[3], tint[1] * tint[3], tint[2] * tint[3], tint[3]}
+
+	var positions, texCoords [4]mgl32.Vec2
+	for i, corner := range unitSquare {
+		positions[i] = model.Mul4x1(mgl32.Vec4{corner.X(), corner.Y(), 0, 1}).Vec2()
+		texCoords[i] = mgl32.Vec2{texture[0] + corner.X()*texture[2], texture[1] + (1-corner.Y())*texture[3]}
+	}
+
+	g.batch.add(textureId, blend, positions, texCoords, bounds, tint)
+	g.drawCalls++
+}
 
-	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+// Converts a color to a tint with non-premultiplied colors, so its alpha can still be changed by the opacity
+func toTint(c color.Color) mgl32.Vec4 {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return mgl32.Vec4{float32(nrgba.R) / 255, float32(nrgba.G) / 255, float32(nrgba.B) / 255, float32(nrgba.A) / 255}
 }
@@ -1,50 +1,123 @@
 package gl
 
 import (
+	"errors"
+	"fmt"
 	"github.com/Hikarikun92/go-game-engine/ui"
 	"github.com/go-gl/gl/v4.1-core/gl"
-	_ "golang.org/x/image/bmp"
-	_ "golang.org/x/image/tiff"
-	_ "golang.org/x/image/webp"
+	"github.com/go-gl/mathgl/mgl32"
 	"image"
 	"image/draw"
-	_ "image/gif"
-	_ "image/jpeg"
-	_ "image/png"
-	"log"
-	"os"
 )
 
 type imageLoaderImpl struct {
+	//Quads drawn with a texture must be flushed before it is deleted, or OpenGL may reuse its name for a new texture
+	batch *batch
 }
 
 type imageImpl struct {
 	textureId uint32
 	width     float32
 	height    float32
+	//Region of the texture used by the image, in texture coordinates (X, Y, width and height); the whole texture
+	//unless it is a sub-image
+	region [4]float32
+	//Whether the texture belongs to another image
+	subImage bool
 }
 
-func (i *imageLoaderImpl) LoadImage(file string) ui.Image {
-	imgFile, err := os.Open(file)
-	if err != nil {
-		log.Fatalf("texture %q not found on disk: %v", file, err)
-	}
+func (i imageImpl) Size() (int, int) {
+	return int(i.width), int(i.height)
+}
 
-	//Decode the image to a know structure (using the imports with _)
-	img, _, err := image.Decode(imgFile)
+func (i *imageLoaderImpl) LoadImage(file string) (ui.Image, error) {
+	img, err := ui.DecodeImage(file)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 
+	return i.CreateImage(img)
+}
+
+func (i *imageLoaderImpl) CreateImage(img image.Image) (ui.Image, error) {
 	rgba := image.NewRGBA(img.Bounds())
 	rgbaSize := rgba.Rect.Size()
 
 	if rgba.Stride != rgbaSize.X*4 {
-		log.Fatalln("unsupported stride")
+		return nil, errors.New("unsupported stride")
+	}
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return imageImpl{
+		textureId: newTexture(rgbaSize.X, rgbaSize.Y, rgba.Pix),
+		width:     float32(rgbaSize.X),
+		height:    float32(rgbaSize.Y),
+		region:    fullTexture,
+	}, nil
+}
+
+func (i *imageLoaderImpl) CreateSubImage(parent ui.Image, region image.Rectangle) (ui.Image, error) {
+	img := parent.(imageImpl)
+
+	bounds := image.Rect(0, 0, int(img.width), int(img.height))
+	if region.Empty() || !region.In(bounds) {
+		return nil, fmt.Errorf("region %v is outside of the image's bounds %v", region, bounds)
+	}
+
+	return imageImpl{
+		textureId: img.textureId,
+		width:     float32(region.Dx()),
+		height:    float32(region.Dy()),
+		region:    img.subRegion(region),
+		subImage:  true,
+	}, nil
+}
+
+func (i *imageLoaderImpl) UnloadImage(image ui.Image) {
+	img := image.(imageImpl)
+	if !img.subImage {
+		if i.batch.texture == img.textureId {
+			i.batch.flush()
+		}
+		gl.DeleteTextures(1, &img.textureId)
+	}
+}
+
+// Converts a rectangle in the image's pixels to texture coordinates, taking into account that the image may itself be a
+// region of the texture. The rectangle is clipped to the image, so a sub-image never reaches the rest of its texture.
+func (i imageImpl) subRegion(rectangle image.Rectangle) [4]float32 {
+	rectangle = rectangle.Intersect(image.Rect(0, 0, int(i.width), int(i.height)))
+
+	scaleX := i.region[2] / i.width
+	scaleY := i.region[3] / i.height
+	return [4]float32{
+		i.region[0] + float32(rectangle.Min.X)*scaleX,
+		i.region[1] + float32(rectangle.Min.Y)*scaleY,
+		float32(rectangle.Dx()) * scaleX,
+		float32(rectangle.Dy()) * scaleY,
+	}
+}
+
+// Returns the texture coordinates that can be sampled when drawing a region of the texture (X, Y, width and height):
+// half a texel inside its edges, which are the centers of its outermost pixels. Past them, the linear filtering would
+// mix in the pixels outside the region.
+func (i imageImpl) sampleBounds(region [4]float32) mgl32.Vec4 {
+	if region == fullTexture {
+		return fullBounds
 	}
-	draw.Draw(rgba, rgba.Bounds(), img, image.Point{X: 0, Y: 0}, draw.Src)
 
-	//Create an OpenGL texture with the image data
+	halfTexelX := 0.5 * i.region[2] / i.width
+	halfTexelY := 0.5 * i.region[3] / i.height
+	return mgl32.Vec4{
+		region[0] + halfTexelX,
+		region[1] + halfTexelY,
+		region[0] + region[2] - halfTexelX,
+		region[1] + region[3] - halfTexelY,
+	}
+}
+
+// Creates an OpenGL texture with the given RGBA pixels
+func newTexture(width int, height int, pixels []uint8) uint32 {
 	var texture uint32
 	gl.GenTextures(1, &texture)
 	gl.ActiveTexture(gl.TEXTURE0)
@@ -54,16 +127,7 @@ func (i *imageLoaderImpl) LoadImage(file string) ui.Image {
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, gl.CLAMP_TO_EDGE)
 	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, gl.CLAMP_TO_EDGE)
 
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(rgbaSize.X), int32(rgbaSize.Y), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(rgba.Pix))
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(width), int32(height), 0, gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(pixels))
 
-	return imageImpl{
-		textureId: texture,
-		width:     float32(rgbaSize.X),
-		height:    float32(rgbaSize.Y),
-	}
-}
-
-func (i *imageLoaderImpl) UnloadImage(image ui.Image) {
-	img := image.(imageImpl)
-	gl.DeleteTextures(1, &img.textureId)
+	return texture
 }
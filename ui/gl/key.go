@@ -252,3 +252,27 @@ func translateKey(glfwKey glfw.Key) key.Key {
 		return key.UNKNOWN
 	}
 }
+
+// Adapter for GLFW's modifier keys and the engine's modifiers
+func translateModifiers(mods glfw.ModifierKey) key.Modifier {
+	var modifiers key.Modifier
+	if mods&glfw.ModShift != 0 {
+		modifiers |= key.MOD_SHIFT
+	}
+	if mods&glfw.ModControl != 0 {
+		modifiers |= key.MOD_CONTROL
+	}
+	if mods&glfw.ModAlt != 0 {
+		modifiers |= key.MOD_ALT
+	}
+	if mods&glfw.ModSuper != 0 {
+		modifiers |= key.MOD_SUPER
+	}
+	if mods&glfw.ModCapsLock != 0 {
+		modifiers |= key.MOD_CAPS_LOCK
+	}
+	if mods&glfw.ModNumLock != 0 {
+		modifiers |= key.MOD_NUM_LOCK
+	}
+	return modifiers
+}
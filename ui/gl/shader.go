@@ -6,37 +6,48 @@ import (
 	"strings"
 )
 
-// Vertex shader using the projection matrix (defining the screen size and orientation), the model's vertices and the
-// model's attributes (position, size etc.). The X and Y coordinates of each vertex are the position of the vertex, and
-// the last 2 coordinates (Z and W) are the coordinates of the associated texture.
+// Vertex shader using the projection matrix (defining the screen size and orientation) and the vertices of the quads
+// batched together. The vertices are already transformed to the screen's coordinates, and carry the coordinates of the
+// associated texture, the bounds of the region of the texture being drawn and the tint multiplied by it (used for the
+// opacity and for filling rectangles with a solid color). Both the textures and the tints have premultiplied alpha.
 var vertexShader = `
 #version 330 core
-layout (location = 0) in vec4 vertexData;
+layout (location = 0) in vec2 position;
+layout (location = 1) in vec2 texCoord;
+layout (location = 2) in vec4 bounds;
+layout (location = 3) in vec4 tint;
 
 out vec2 TexCoord;
+out vec4 Bounds;
+out vec4 Tint;
 
-uniform mat4 model;
 uniform mat4 projection;
 
 void main()
 {
-	gl_Position = projection * model * vec4(vertexData.xy, 0.0, 1.0);
-	TexCoord = vertexData.zw;
+	gl_Position = projection * vec4(position, 0.0, 1.0);
+	TexCoord = texCoord;
+	Bounds = bounds;
+	Tint = tint;
 }
 ` + "\x00"
 
-// Fragment shader that will simply retrieve the texture's color at the specified point.
+// Fragment shader that will retrieve the texture's color at the specified point, multiplied by the vertex's tint. The
+// point is kept inside the bounds, so the linear filtering never mixes in the pixels around the region being drawn
+// (such as the neighbouring frames of a sprite sheet).
 var fragmentShader = `
 #version 330
 
 uniform sampler2D tex;
 
 in vec2 TexCoord;
+in vec4 Bounds;
+in vec4 Tint;
 
 out vec4 outputColor;
 
 void main() {
-    outputColor = texture(tex, TexCoord);
+    outputColor = texture(tex, clamp(TexCoord, Bounds.xy, Bounds.zw)) * Tint;
 }
 ` + "\x00"
 
@@ -1,14 +1,15 @@
 package gl
 
 import (
+	"fmt"
 	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
 	"github.com/Hikarikun92/go-game-engine/key"
 	"github.com/Hikarikun92/go-game-engine/settings"
 	"github.com/Hikarikun92/go-game-engine/ui"
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-	"log"
 	"runtime"
 )
 
@@ -20,11 +21,24 @@ func NewWindowManager() ui.WindowManager {
 }
 
 type windowImpl struct {
-	glfwWindow          *glfw.Window
-	vertexArrayObject   uint32
-	vertexBufferObject  uint32
-	elementBufferObject uint32
-	shaderProgram       uint32
+	glfwWindow    *glfw.Window
+	batch         *batch
+	shaderProgram uint32
+	whiteTexture  uint32
+
+	settings *settings.Settings
+	//Area where the game is drawn, in screen coordinates (used by the cursor) and in pixels (used by OpenGL); they are
+	//different on high DPI monitors
+	viewport            ui.Viewport
+	framebufferViewport ui.Viewport
+	resizeListener      ui.ResizeListener
+
+	mode settings.WindowMode
+	//Position and size of the window before switching to fullscreen, restored when going back to windowed mode
+	windowedX      int
+	windowedY      int
+	windowedWidth  int
+	windowedHeight int
 }
 
 /*
@@ -33,15 +47,19 @@ https://learnopengl.com/Getting-started/Textures
 https://learnopengl.com/In-Practice/2D-Game/Rendering-Sprites
 https://github.com/go-gl/example/blob/master/gl41core-cube/cube.go
 */
-func (*glWindowManager) CreateMainWindow(settings *settings.Settings) ui.Window {
+func (*glWindowManager) CreateMainWindow(settings *settings.Settings) (ui.Window, error) {
 	// GLFW event handling must run on the main OS thread
 	runtime.LockOSThread()
 
 	//Initialize GLFW and create an invisible window
 	if err := glfw.Init(); err != nil {
-		log.Fatalln("Failed to initialize GLFW:", err)
+		return nil, fmt.Errorf("failed to initialize GLFW: %w", err)
+	}
+	if settings.Resizable {
+		glfw.WindowHint(glfw.Resizable, glfw.True)
+	} else {
+		glfw.WindowHint(glfw.Resizable, glfw.False)
 	}
-	glfw.WindowHint(glfw.Resizable, glfw.False)
 	glfw.WindowHint(glfw.Visible, glfw.False)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
@@ -50,30 +68,39 @@ func (*glWindowManager) CreateMainWindow(settings *settings.Settings) ui.Window
 
 	window, err := glfw.CreateWindow(settings.Width, settings.Height, settings.WindowTitle, nil, nil)
 	if err != nil {
-		log.Fatalln("Failed to create window:", err)
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to create window: %w", err)
 	}
 	window.MakeContextCurrent()
+	glfw.SwapInterval(swapInterval(settings.FramePacing))
 
-	//Center the window on screen
-	videoMode := glfw.GetPrimaryMonitor().GetVideoMode()
-	windowX := (videoMode.Width - settings.Width) / 2
-	windowY := (videoMode.Height - settings.Height) / 2
+	//Center the window on the chosen monitor; it only goes fullscreen after everything is initialized
+	monitor := selectMonitor(settings.Monitor)
+	monitorX, monitorY := monitor.GetPos()
+	videoMode := monitor.GetVideoMode()
+	windowX := monitorX + (videoMode.Width-settings.Width)/2
+	windowY := monitorY + (videoMode.Height-settings.Height)/2
 
 	window.SetPos(windowX, windowY)
 
 	// Initialize Glow
 	if err := gl.Init(); err != nil {
-		log.Fatalln(err)
+		window.Destroy()
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to initialize OpenGL: %w", err)
 	}
 
 	//Create the main shader program
 	shaderProgram, err := newShaderProgram()
 	if err != nil {
-		log.Fatalln("Failed to create shader program:", err)
+		window.Destroy()
+		glfw.Terminate()
+		return nil, fmt.Errorf("failed to create shader program: %w", err)
 	}
 
 	gl.UseProgram(shaderProgram)
 
+	//The projection always uses the logical resolution; the viewport scales it to the window's size
 	//Usually you would set HEIGHT as the bottom value and 0 as the top, but I'm deliberately inverting it here
 	projection := mgl32.Ortho2D(0.0, float32(settings.Width), 0, float32(settings.Height))
 
@@ -85,84 +112,238 @@ func (*glWindowManager) CreateMainWindow(settings *settings.Settings) ui.Window
 	textureUniform := gl.GetUniformLocation(shaderProgram, gl.Str("tex\x00"))
 	gl.Uniform1i(textureUniform, 0)
 
-	//Create a square that will be used to draw the images
-	vertices := []float32{
-		// pos    // tex
-		0.0, 1.0, 0.0, 0.0, //top left vertex, bottom left texture
-		1.0, 0.0, 1.0, 1.0, //bottom right vertex, top right texture
-		0.0, 0.0, 0.0, 1.0, //bottom left vertex, top left texture
-		1.0, 1.0, 1.0, 0.0, //top right, bottom right texture
-	}
-	//Indices defining which vertices to use (useful to avoid specifying duplicate vertices in the array)
-	indices := []int32{
-		0, 1, 2,
-		0, 3, 1,
-	}
+	//Blending is only enabled when drawing something translucent, but it always uses the same function. The textures
+	//have premultiplied alpha (like image.RGBA) and so do the tints, so the source colors are already scaled by it.
+	gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
 
-	var vao, vbo, ebo uint32
-	gl.GenVertexArrays(1, &vao)
-	gl.GenBuffers(1, &vbo)
-	gl.GenBuffers(1, &ebo)
+	//A single white pixel, tinted to fill rectangles with solid colors
+	whiteTexture := newTexture(1, 1, []uint8{255, 255, 255, 255})
 
-	//Work on this specific object
-	gl.BindVertexArray(vao)
+	//Sprites are accumulated and drawn together, to avoid a draw call for each one
+	batch := newBatch()
 
-	//Load the vertices into memory
-	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-
-	//Load the indices into memory
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+	w := &windowImpl{
+		glfwWindow:    window,
+		batch:         batch,
+		shaderProgram: shaderProgram,
+		whiteTexture:  whiteTexture,
+		settings:      settings,
+	}
 
-	//Position and texture attributes (the "location = 0" in the shader)
-	gl.VertexAttribPointerWithOffset(0, 4, gl.FLOAT, false, 4*4 /* 4 values per vertex * 4 bytes per value */, 0)
-	gl.EnableVertexAttribArray(0)
+	w.updateViewports()
+	window.SetFramebufferSizeCallback(func(glfwWindow *glfw.Window, width int, height int) {
+		w.updateViewports()
+	})
+	window.SetSizeCallback(func(glfwWindow *glfw.Window, width int, height int) {
+		w.updateViewports()
+		if w.resizeListener != nil {
+			w.resizeListener.WindowResized(width, height)
+		}
+	})
 
+	w.SetWindowMode(settings.WindowMode)
 	window.Show()
 
-	return &windowImpl{
-		glfwWindow:          window,
-		vertexArrayObject:   vao,
-		vertexBufferObject:  vbo,
-		elementBufferObject: ebo,
-		shaderProgram:       shaderProgram,
+	return w, nil
+}
+
+// Returns how many monitor refreshes to wait before swapping the buffers. Only vsync waits for the driver, the other
+// pacings are done by the game loop.
+func swapInterval(pacing settings.FramePacing) int {
+	if pacing == settings.VSYNC {
+		return 1
+	}
+	return 0
+}
+
+// Returns the monitor with the given index, or the primary one if there's no such monitor (e.g. it was disconnected
+// since the settings were saved)
+func selectMonitor(index int) *glfw.Monitor {
+	monitors := glfw.GetMonitors()
+	if index < 0 || index >= len(monitors) {
+		return glfw.GetPrimaryMonitor()
 	}
+	return monitors[index]
+}
+
+// Recalculates where the game is drawn after the window's size changes
+func (w *windowImpl) updateViewports() {
+	scaling := w.settings.Scaling
+	logicalWidth := w.settings.Width
+	logicalHeight := w.settings.Height
+
+	width, height := w.glfwWindow.GetSize()
+	w.viewport = ui.NewViewport(scaling, logicalWidth, logicalHeight, width, height)
+
+	framebufferWidth, framebufferHeight := w.glfwWindow.GetFramebufferSize()
+	w.framebufferViewport = ui.NewViewport(scaling, logicalWidth, logicalHeight, framebufferWidth, framebufferHeight)
+
+	v := w.framebufferViewport
+	gl.Viewport(int32(v.X), int32(v.Y), int32(v.Width), int32(v.Height))
 }
 
-func (w *windowImpl) SetKeyListener(listener key.Listener) {
+func (w *windowImpl) SetKeyListener(listener key.EventListener) {
 	//Adapter between the engine's listener and GLFW's listener
 	w.glfwWindow.SetKeyCallback(func(w *glfw.Window, glfwKey glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		k := translateKey(glfwKey)
-		if k == key.UNKNOWN {
-			return //Ignore unknown keys
+		event := key.Event{Key: translateKey(glfwKey), Scancode: scancode, Modifiers: translateModifiers(mods)}
+
+		switch action {
+		case glfw.Press:
+			event.Action = key.PRESS
+		case glfw.Release:
+			event.Action = key.RELEASE
+		case glfw.Repeat:
+			event.Action = key.REPEAT
+		default:
+			return
 		}
 
+		listener.KeyEvent(event)
+	})
+}
+
+func (w *windowImpl) SetTextListener(textListener key.TextListener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetCharCallback(func(w *glfw.Window, char rune) {
+		textListener.CharacterTyped(char)
+	})
+}
+
+func (w *windowImpl) SetCursorListener(cursorListener cursor.Listener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetCursorPosCallback(func(glfwWindow *glfw.Window, xpos float64, ypos float64) {
+		cursorListener.CursorMoved(w.viewport.ToLogical(xpos, ypos))
+	})
+}
+
+func (w *windowImpl) SetButtonListener(buttonListener cursor.ButtonListener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetMouseButtonCallback(func(glfwWindow *glfw.Window, glfwButton glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		//GLFW doesn't report where the button was pressed, but the cursor hasn't moved since the last position event
+		x, y := w.viewport.ToLogical(glfwWindow.GetCursorPos())
+		button := translateButton(glfwButton)
+		modifiers := translateModifiers(mods)
+
 		if action == glfw.Press {
-			listener.KeyPressed(k)
+			buttonListener.ButtonPressed(button, x, y, modifiers)
 		} else if action == glfw.Release {
-			listener.KeyReleased(k)
+			buttonListener.ButtonReleased(button, x, y, modifiers)
 		}
 	})
 }
 
-func (w *windowImpl) SetCursorListener(cursorListener cursor.Listener) {
+func (w *windowImpl) SetScrollListener(scrollListener cursor.ScrollListener) {
 	//Adapter between the engine's listener and GLFW's listener
-	w.glfwWindow.SetCursorPosCallback(func(w *glfw.Window, xpos float64, ypos float64) {
-		cursorListener.CursorMoved(int(xpos), int(ypos))
+	w.glfwWindow.SetScrollCallback(func(w *glfw.Window, xoff float64, yoff float64) {
+		scrollListener.Scrolled(xoff, yoff)
 	})
 }
 
+func (w *windowImpl) SetResizeListener(resizeListener ui.ResizeListener) {
+	w.resizeListener = resizeListener
+}
+
+func (w *windowImpl) SetFocusListener(focusListener ui.FocusListener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetFocusCallback(func(glfwWindow *glfw.Window, focused bool) {
+		if focused {
+			focusListener.FocusGained()
+		} else {
+			focusListener.FocusLost()
+		}
+	})
+}
+
+func (w *windowImpl) SetMinimizeListener(minimizeListener ui.MinimizeListener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetIconifyCallback(func(glfwWindow *glfw.Window, iconified bool) {
+		if iconified {
+			minimizeListener.Minimized()
+		} else {
+			minimizeListener.Restored()
+		}
+	})
+}
+
+func (w *windowImpl) SetCloseListener(closeListener ui.CloseListener) {
+	//GLFW has already flagged the window to close when the callback runs, so a veto only needs to undo it
+	w.glfwWindow.SetCloseCallback(func(glfwWindow *glfw.Window) {
+		if !closeListener.CloseRequested() {
+			glfwWindow.SetShouldClose(false)
+		}
+	})
+}
+
+func (w *windowImpl) SetDropListener(dropListener ui.DropListener) {
+	//Adapter between the engine's listener and GLFW's listener
+	w.glfwWindow.SetDropCallback(func(glfwWindow *glfw.Window, names []string) {
+		dropListener.FilesDropped(names)
+	})
+}
+
+func (w *windowImpl) SetWindowMode(mode settings.WindowMode) {
+	if mode == w.mode {
+		return
+	}
+	if w.mode == settings.WINDOWED {
+		w.windowedX, w.windowedY = w.glfwWindow.GetPos()
+		w.windowedWidth, w.windowedHeight = w.glfwWindow.GetSize()
+	}
+
+	//Changing the monitor keeps the OpenGL context, so the loaded textures and buffers are still valid afterwards
+	switch mode {
+	case settings.BORDERLESS:
+		//GLFW keeps the monitor's video mode when the requested one is the same as the current one
+		monitor := selectMonitor(w.settings.Monitor)
+		current := monitor.GetVideoMode()
+		w.glfwWindow.SetMonitor(monitor, 0, 0, current.Width, current.Height, current.RefreshRate)
+	case settings.FULLSCREEN:
+		//GLFW chooses the supported video mode closest to the requested one
+		monitor := selectMonitor(w.settings.Monitor)
+		current := monitor.GetVideoMode()
+		videoMode := w.settings.VideoMode
+		width, height, refreshRate := videoMode.Width, videoMode.Height, videoMode.RefreshRate
+		if width <= 0 || height <= 0 {
+			width, height = current.Width, current.Height
+		}
+		if refreshRate <= 0 {
+			refreshRate = current.RefreshRate
+		}
+		w.glfwWindow.SetMonitor(monitor, 0, 0, width, height, refreshRate)
+	default:
+		mode = settings.WINDOWED
+		w.glfwWindow.SetMonitor(nil, w.windowedX, w.windowedY, w.windowedWidth, w.windowedHeight, 0)
+	}
+
+	w.mode = mode
+}
+
+func (w *windowImpl) WindowMode() settings.WindowMode {
+	return w.mode
+}
+
 func (w *windowImpl) CreateImageLoader() ui.ImageLoader {
-	return &imageLoaderImpl{}
+	return &imageLoaderImpl{batch: w.batch}
+}
+
+func (w *windowImpl) CreateGamepadPoller() gamepad.Poller {
+	return &gamepadPollerImpl{}
 }
 
 func (w *windowImpl) CreateGraphics() ui.Graphics {
-	//Clear the screen before delegating the drawing to the current state
+	//Clear the screen before delegating the drawing to the current state (the clip also affects the clearing, but the
+	//viewport doesn't, so this also paints the bars around it)
+	gl.Disable(gl.SCISSOR_TEST)
 	gl.ClearColor(0.0, 0.0, 0.0, 1.0)
 	gl.Clear(gl.COLOR_BUFFER_BIT)
+	w.batch.flushes = 0
 
-	return &graphicsImpl{shaderProgram: w.shaderProgram}
+	return &graphicsImpl{
+		batch:        w.batch,
+		whiteTexture: w.whiteTexture,
+		viewport:     w.framebufferViewport,
+		opacity:      1.0,
+	}
 }
 
 func (w *windowImpl) ShouldClose() bool {
@@ -170,16 +351,17 @@ func (w *windowImpl) ShouldClose() bool {
 }
 
 func (w *windowImpl) Update() {
+	//Draw whatever is still queued before showing the frame
+	w.batch.flush()
 	w.glfwWindow.SwapBuffers()
 	glfw.PollEvents()
 }
 
 func (w *windowImpl) Destroy() {
 	//Delete the objects allocated in memory
-	gl.DeleteVertexArrays(1, &w.vertexArrayObject)
-	gl.DeleteBuffers(1, &w.vertexBufferObject)
-	gl.DeleteBuffers(1, &w.elementBufferObject)
+	w.batch.delete()
 	gl.DeleteProgram(w.shaderProgram)
+	gl.DeleteTextures(1, &w.whiteTexture)
 
 	//Release the rest of the memory
 	w.glfwWindow.Destroy()
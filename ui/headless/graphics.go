@@ -0,0 +1,197 @@
+package headless
+
+import (
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"github.com/go-gl/mathgl/mgl32"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type graphicsImpl struct {
+	target *image.RGBA
+
+	offset  image.Point
+	clip    image.Rectangle
+	opacity float32
+
+	drawCalls int
+}
+
+func newGraphics(target *image.RGBA) *graphicsImpl {
+	return &graphicsImpl{target: target, clip: target.Bounds(), opacity: 1.0}
+}
+
+func (g *graphicsImpl) DrawImage(image ui.Image, x int, y int) {
+	img := image.(imageImpl)
+	destination := g.toTarget(x+g.offset.X, y+g.offset.Y, img.rgba.Rect.Size())
+
+	if g.opacity >= 1.0 {
+		//The OpenGL backend doesn't blend opaque draws, so the texture replaces whatever was drawn before
+		g.draw(destination, img.rgba, img.rgba.Rect.Min, nil, draw.Src)
+	} else {
+		g.draw(destination, img.rgba, img.rgba.Rect.Min, g.opacityMask(), draw.Over)
+	}
+}
+
+// DrawImageOptions samples the nearest pixel of the image for each pixel covered by it, the same way the OpenGL backend
+// transforms the unit square.
+func (g *graphicsImpl) DrawImageOptions(texture ui.Image, options ui.DrawOptions) {
+	g.drawCalls++
+	img := texture.(imageImpl)
+	source := options.SourceRectangle(img).Add(img.rgba.Rect.Min)
+	size := source.Size()
+	if source.Empty() {
+		return
+	}
+
+	model := mgl32.Translate2D(options.X+float32(g.offset.X), options.Y+float32(g.offset.Y))
+	model = model.Mul3(mgl32.HomogRotate2D(options.Rotation))
+	scaleX, scaleY := options.Scale()
+	model = model.Mul3(mgl32.Scale2D(scaleX, scaleY))
+	model = model.Mul3(mgl32.Translate2D(-options.OriginX, -options.OriginY))
+	model = model.Mul3(mgl32.Scale2D(float32(size.X), float32(size.Y)))
+	if options.FlipX {
+		model = model.Mul3(mgl32.Translate2D(1.0, 0)).Mul3(mgl32.Scale2D(-1.0, 1.0))
+	}
+	if options.FlipY {
+		model = model.Mul3(mgl32.Translate2D(0, 1.0)).Mul3(mgl32.Scale2D(1.0, -1.0))
+	}
+
+	if model.Det() == 0 {
+		return //Scaled to nothing
+	}
+	inverse := model.Inv()
+
+	//Only the pixels inside the transformed square need to be checked
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for _, corner := range []mgl32.Vec3{{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}} {
+		point := model.Mul3x1(corner)
+		minX, maxX = min32(minX, point.X()), max32(maxX, point.X())
+		minY, maxY = min32(minY, point.Y()), max32(maxY, point.Y())
+	}
+	left, bottom := int(math.Floor(float64(minX))), int(math.Floor(float64(minY)))
+	right, top := int(math.Ceil(float64(maxX))), int(math.Ceil(float64(maxY)))
+	area := g.toTarget(left, bottom, image.Point{X: right - left, Y: top - bottom}).Intersect(g.clip)
+
+	red, green, blue, alpha := float32(1.0), float32(1.0), float32(1.0), float32(1.0)
+	if options.Tint != nil {
+		nrgba := color.NRGBAModel.Convert(options.Tint).(color.NRGBA)
+		red, green, blue, alpha = float32(nrgba.R)/255, float32(nrgba.G)/255, float32(nrgba.B)/255, float32(nrgba.A)/255
+	}
+	alpha = clamp(alpha * options.Opacity() * g.opacity)
+
+	height := g.target.Rect.Dy()
+	for targetY := area.Min.Y; targetY < area.Max.Y; targetY++ {
+		for targetX := area.Min.X; targetX < area.Max.X; targetX++ {
+			//Sample at the center of the pixel, converted back to the engine's bottom-left origin
+			point := inverse.Mul3x1(mgl32.Vec3{float32(targetX) + 0.5, float32(height-targetY) - 0.5, 1})
+			u, v := point.X(), point.Y()
+			if u < 0 || u >= 1 || v < 0 || v >= 1 {
+				continue
+			}
+
+			//The bottom of the unit square is the last row of the region
+			sourceX := source.Min.X + int(u*float32(size.X))
+			sourceY := source.Max.Y - 1 - int(v*float32(size.Y))
+			pixel := img.rgba.RGBAAt(sourceX, sourceY)
+
+			//Both colors are premultiplied, so the tint's alpha scales every channel
+			r := float32(pixel.R) * red * alpha
+			gr := float32(pixel.G) * green * alpha
+			b := float32(pixel.B) * blue * alpha
+			a := float32(pixel.A) * alpha
+
+			if alpha < 1.0 {
+				//Like the OpenGL backend, only translucent draws are blended
+				destination := g.target.RGBAAt(targetX, targetY)
+				remaining := 1 - a/255
+				r += float32(destination.R) * remaining
+				gr += float32(destination.G) * remaining
+				b += float32(destination.B) * remaining
+				a += float32(destination.A) * remaining
+			}
+
+			g.target.SetRGBA(targetX, targetY, color.RGBA{R: toByte(r), G: toByte(gr), B: toByte(b), A: toByte(a)})
+		}
+	}
+}
+
+func (g *graphicsImpl) FillRectangle(x int, y int, width int, height int, c color.Color) {
+	destination := g.toTarget(x+g.offset.X, y+g.offset.Y, image.Point{X: width, Y: height})
+	g.draw(destination, image.NewUniform(c), image.Point{}, g.opacityMask(), draw.Over)
+}
+
+func (g *graphicsImpl) SetOffset(x int, y int) {
+	g.offset = image.Point{X: x, Y: y}
+}
+
+func (g *graphicsImpl) SetClip(x int, y int, width int, height int) {
+	g.clip = g.toTarget(x, y, image.Point{X: width, Y: height}).Intersect(g.target.Bounds())
+}
+
+func (g *graphicsImpl) ClearClip() {
+	g.clip = g.target.Bounds()
+}
+
+func (g *graphicsImpl) SetOpacity(opacity float32) {
+	g.opacity = opacity
+}
+
+func (g *graphicsImpl) DrawCalls() int {
+	return g.drawCalls
+}
+
+// Draws the source in the destination rectangle, restricted to the clipping area
+func (g *graphicsImpl) draw(destination image.Rectangle, source image.Image, sourcePoint image.Point, mask image.Image, op draw.Op) {
+	g.drawCalls++
+
+	clipped := destination.Intersect(g.clip)
+	if clipped.Empty() {
+		return
+	}
+
+	sourcePoint = sourcePoint.Add(clipped.Min.Sub(destination.Min))
+	draw.DrawMask(g.target, clipped, source, sourcePoint, mask, image.Point{}, op)
+}
+
+func (g *graphicsImpl) opacityMask() image.Image {
+	return image.NewUniform(color.Alpha{A: uint8(clamp(g.opacity) * 255)})
+}
+
+func clamp(value float32) float32 {
+	if value < 0.0 {
+		return 0.0
+	} else if value > 1.0 {
+		return 1.0
+	}
+	return value
+}
+
+func toByte(value float32) uint8 {
+	return uint8(math.Min(math.Round(float64(value)), 255))
+}
+
+func min32(a float32, b float32) float32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a float32, b float32) float32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Converts a rectangle from the engine's coordinates to the target's coordinates. The engine uses a bottom-left origin
+// (like the ortho projection of the OpenGL backend), while the rows of an image.RGBA start at the top, so the Y axis
+// must be inverted.
+func (g *graphicsImpl) toTarget(x int, y int, size image.Point) image.Rectangle {
+	top := g.target.Rect.Dy() - y - size.Y
+	return image.Rect(x, top, x+size.X, top+size.Y)
+}
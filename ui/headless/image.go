@@ -0,0 +1,57 @@
+package headless
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"image/draw"
+)
+
+type imageLoaderImpl struct {
+}
+
+type imageImpl struct {
+	rgba *image.RGBA
+}
+
+func (i *imageLoaderImpl) LoadImage(file string) (ui.Image, error) {
+	img, err := ui.DecodeImage(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.CreateImage(img)
+}
+
+func (i *imageLoaderImpl) CreateImage(img image.Image) (ui.Image, error) {
+	return NewImage(img), nil
+}
+
+func (i *imageLoaderImpl) CreateSubImage(parent ui.Image, region image.Rectangle) (ui.Image, error) {
+	img := parent.(imageImpl)
+
+	bounds := img.rgba.Rect.Sub(img.rgba.Rect.Min)
+	if region.Empty() || !region.In(bounds) {
+		return nil, fmt.Errorf("region %v is outside of the image's bounds %v", region, bounds)
+	}
+
+	//The sub-image shares the pixels of the original one
+	return imageImpl{rgba: img.rgba.SubImage(region.Add(img.rgba.Rect.Min)).(*image.RGBA)}, nil
+}
+
+func (i *imageLoaderImpl) UnloadImage(image ui.Image) {
+	//Nothing to release, the garbage collector takes care of the pixels
+}
+
+func (i imageImpl) Size() (int, int) {
+	return i.rgba.Rect.Dx(), i.rgba.Rect.Dy()
+}
+
+// NewImage converts an already decoded image into a ui.Image that can be drawn by this backend.
+func NewImage(img image.Image) ui.Image {
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	return imageImpl{rgba: rgba}
+}
@@ -0,0 +1,346 @@
+package headless
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"github.com/Hikarikun92/go-game-engine/ui"
+	"image"
+	"image/color"
+	"image/draw"
+	"sync"
+	"time"
+)
+
+// Refresh rate of the simulated monitor when neither the video mode nor the frame limit choose one
+const defaultRefreshRate = 60
+
+// WindowManager is a ui.WindowManager that renders into memory instead of an OpenGL context, so the engine can run
+// without a display or a GPU (e.g. in tests and on CI machines).
+type WindowManager struct {
+	window *Window
+}
+
+func NewWindowManager() *WindowManager {
+	return &WindowManager{}
+}
+
+// Window returns the window created by CreateMainWindow, or nil if it wasn't created yet.
+func (m *WindowManager) Window() *Window {
+	return m.window
+}
+
+// Window is the in-memory counterpart of the OpenGL window. Drawing happens on a back buffer, which becomes the last
+// rendered frame when Update is called (like swapping buffers). The frames always have the game's logical resolution,
+// but the window can be resized to check how the input is mapped to it.
+type Window struct {
+	settings *settings.Settings
+	viewport ui.Viewport
+	mode     settings.WindowMode
+
+	keyListener      key.EventListener
+	textListener     key.TextListener
+	cursorListener   cursor.Listener
+	buttonListener   cursor.ButtonListener
+	scrollListener   cursor.ScrollListener
+	resizeListener   ui.ResizeListener
+	focusListener    ui.FocusListener
+	minimizeListener ui.MinimizeListener
+	closeListener    ui.CloseListener
+	dropListener     ui.DropListener
+	gamepads         *gamepad.FakePoller
+
+	//Time between the refreshes of the simulated monitor, if the frames are synchronized with it
+	refreshPeriod time.Duration
+	nextRefresh   time.Time
+
+	mutex       sync.Mutex
+	backBuffer  *image.RGBA
+	frontBuffer *image.RGBA
+	frames      int
+	shouldClose bool
+}
+
+func (m *WindowManager) CreateMainWindow(settings *settings.Settings) (ui.Window, error) {
+	if settings.Width <= 0 || settings.Height <= 0 {
+		return nil, fmt.Errorf("invalid window size %dx%d", settings.Width, settings.Height)
+	}
+	bounds := image.Rect(0, 0, settings.Width, settings.Height)
+
+	m.window = &Window{
+		settings:    settings,
+		viewport:    ui.NewViewport(settings.Scaling, settings.Width, settings.Height, settings.Width, settings.Height),
+		mode:        settings.WindowMode,
+		backBuffer:  image.NewRGBA(bounds),
+		frontBuffer: image.NewRGBA(bounds),
+		gamepads:    gamepad.NewFakePoller(),
+
+		refreshPeriod: refreshPeriod(settings),
+	}
+	return m.window, nil
+}
+
+func (w *Window) SetKeyListener(keyListener key.EventListener) {
+	w.keyListener = keyListener
+}
+
+func (w *Window) SetTextListener(textListener key.TextListener) {
+	w.textListener = textListener
+}
+
+func (w *Window) SetCursorListener(cursorListener cursor.Listener) {
+	w.cursorListener = cursorListener
+}
+
+func (w *Window) SetButtonListener(buttonListener cursor.ButtonListener) {
+	w.buttonListener = buttonListener
+}
+
+func (w *Window) SetScrollListener(scrollListener cursor.ScrollListener) {
+	w.scrollListener = scrollListener
+}
+
+func (w *Window) SetResizeListener(resizeListener ui.ResizeListener) {
+	w.resizeListener = resizeListener
+}
+
+func (w *Window) SetFocusListener(focusListener ui.FocusListener) {
+	w.focusListener = focusListener
+}
+
+func (w *Window) SetMinimizeListener(minimizeListener ui.MinimizeListener) {
+	w.minimizeListener = minimizeListener
+}
+
+func (w *Window) SetCloseListener(closeListener ui.CloseListener) {
+	w.closeListener = closeListener
+}
+
+func (w *Window) SetDropListener(dropListener ui.DropListener) {
+	w.dropListener = dropListener
+}
+
+// SetWindowMode only records the mode, since there's no monitor to take.
+func (w *Window) SetWindowMode(mode settings.WindowMode) {
+	w.mode = mode
+}
+
+func (w *Window) WindowMode() settings.WindowMode {
+	return w.mode
+}
+
+func (w *Window) CreateImageLoader() ui.ImageLoader {
+	return &imageLoaderImpl{}
+}
+
+func (w *Window) CreateGamepadPoller() gamepad.Poller {
+	return w.gamepads
+}
+
+func (w *Window) CreateGraphics() ui.Graphics {
+	//Clear the screen before delegating the drawing to the current state
+	draw.Draw(w.backBuffer, w.backBuffer.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	return newGraphics(w.backBuffer)
+}
+
+func (w *Window) ShouldClose() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.shouldClose
+}
+
+// Update presents the frame. With vsync, it first waits for the next refresh of the simulated monitor, like swapping
+// the buffers of the OpenGL window does.
+func (w *Window) Update() {
+	w.waitForRefresh()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	//Equivalent of swapping the buffers: the back buffer becomes the visible frame
+	w.backBuffer, w.frontBuffer = w.frontBuffer, w.backBuffer
+	w.frames++
+}
+
+// There's no monitor to synchronize with, so vsync waits for the refreshes of a simulated one running at the refresh
+// rate of the video mode or, if it isn't set, at the frame limit (60 Hz if neither is set)
+func (w *Window) waitForRefresh() {
+	if w.refreshPeriod <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if w.nextRefresh.IsZero() || now.Sub(w.nextRefresh) > w.refreshPeriod {
+		//A refresh was missed: wait for the following one
+		w.nextRefresh = now
+	}
+	time.Sleep(w.nextRefresh.Sub(now))
+	w.nextRefresh = w.nextRefresh.Add(w.refreshPeriod)
+}
+
+// Returns the time between the refreshes of the simulated monitor, or zero if the frames aren't synchronized with it
+func refreshPeriod(s *settings.Settings) time.Duration {
+	if s.FramePacing != settings.VSYNC {
+		return 0
+	}
+
+	rate := defaultRefreshRate
+	if s.VideoMode.RefreshRate > 0 {
+		rate = s.VideoMode.RefreshRate
+	} else if s.Fps > 0 {
+		rate = s.Fps
+	}
+	return time.Second / time.Duration(rate)
+}
+
+func (w *Window) Destroy() {
+}
+
+// Close makes ShouldClose return true, ending the game loop like closing a real window would. It is safe to call from
+// any goroutine.
+func (w *Window) Close() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.shouldClose = true
+}
+
+// RequestClose simulates the player trying to close the window, which only closes it if the close listener allows it.
+// Unlike Close, it must be called from the game's goroutine (e.g. from a state), like the other simulated events.
+func (w *Window) RequestClose() {
+	if w.closeListener != nil && !w.closeListener.CloseRequested() {
+		return
+	}
+	w.Close()
+}
+
+// LastFrame returns a copy of the last frame presented by Update, with the same orientation as the screen (the first
+// row is the top of the window).
+func (w *Window) LastFrame() *image.RGBA {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	frame := image.NewRGBA(w.frontBuffer.Bounds())
+	copy(frame.Pix, w.frontBuffer.Pix)
+	return frame
+}
+
+// Frames returns how many frames were presented so far.
+func (w *Window) Frames() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.frames
+}
+
+// Gamepads returns the fake gamepads seen by the game, which can be connected and manipulated by tests.
+func (w *Window) Gamepads() *gamepad.FakePoller {
+	return w.gamepads
+}
+
+// Resize simulates the player resizing the window, changing how the cursor's coordinates are mapped to the logical
+// resolution.
+func (w *Window) Resize(width int, height int) {
+	w.viewport = ui.NewViewport(w.settings.Scaling, w.settings.Width, w.settings.Height, width, height)
+	if w.resizeListener != nil {
+		w.resizeListener.WindowResized(width, height)
+	}
+}
+
+// Focus simulates the window gaining or losing the keyboard focus.
+func (w *Window) Focus(focused bool) {
+	if w.focusListener == nil {
+		return
+	}
+	if focused {
+		w.focusListener.FocusGained()
+	} else {
+		w.focusListener.FocusLost()
+	}
+}
+
+// Minimize simulates the player minimizing or restoring the window.
+func (w *Window) Minimize(minimized bool) {
+	if w.minimizeListener == nil {
+		return
+	}
+	if minimized {
+		w.minimizeListener.Minimized()
+	} else {
+		w.minimizeListener.Restored()
+	}
+}
+
+// DropFiles simulates the player dropping files on the window.
+func (w *Window) DropFiles(paths ...string) {
+	if w.dropListener != nil {
+		w.dropListener.FilesDropped(paths)
+	}
+}
+
+// PressKey simulates the player pressing a key.
+func (w *Window) PressKey(k key.Key) {
+	w.SendKeyEvent(key.Event{Key: k, Action: key.PRESS})
+}
+
+// ReleaseKey simulates the player releasing a key.
+func (w *Window) ReleaseKey(k key.Key) {
+	w.SendKeyEvent(key.Event{Key: k, Action: key.RELEASE})
+}
+
+// SendKeyEvent simulates any key event, such as a repetition or a press with modifiers.
+func (w *Window) SendKeyEvent(event key.Event) {
+	if w.keyListener != nil {
+		w.keyListener.KeyEvent(event)
+	}
+}
+
+// TypeText simulates the player typing each character of a text.
+func (w *Window) TypeText(text string) {
+	if w.textListener != nil {
+		for _, char := range text {
+			w.textListener.CharacterTyped(char)
+		}
+	}
+}
+
+// MoveCursor simulates the player moving the cursor. Like the events coming from the operating system, the coordinates
+// are relative to the top left corner of the window.
+func (w *Window) MoveCursor(x int, y int) {
+	if w.cursorListener != nil {
+		w.cursorListener.CursorMoved(w.toLogical(x, y))
+	}
+}
+
+// PressButton simulates the player pressing a mouse button, with the coordinates relative to the top left corner of the
+// window.
+func (w *Window) PressButton(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	if w.buttonListener != nil {
+		x, y = w.toLogical(x, y)
+		w.buttonListener.ButtonPressed(button, x, y, modifiers)
+	}
+}
+
+// ReleaseButton simulates the player releasing a mouse button, with the coordinates relative to the top left corner of
+// the window.
+func (w *Window) ReleaseButton(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	if w.buttonListener != nil {
+		x, y = w.toLogical(x, y)
+		w.buttonListener.ButtonReleased(button, x, y, modifiers)
+	}
+}
+
+// Scroll simulates the player using the mouse wheel.
+func (w *Window) Scroll(xOffset float64, yOffset float64) {
+	if w.scrollListener != nil {
+		w.scrollListener.Scrolled(xOffset, yOffset)
+	}
+}
+
+func (w *Window) toLogical(x int, y int) (int, int) {
+	return w.viewport.ToLogical(float64(x), float64(y))
+}
@@ -0,0 +1,182 @@
+package headless
+
+import (
+	"fmt"
+	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/key"
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+var red = color.RGBA{R: 255, A: 255}
+var blue = color.RGBA{B: 255, A: 255}
+
+func newWindow(t *testing.T, width int, height int) *Window {
+	s := settings.DefaultSettings()
+	s.Width, s.Height = width, height
+
+	window, err := NewWindowManager().CreateMainWindow(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return window.(*Window)
+}
+
+func TestLastFrameOrientation(t *testing.T) {
+	window := newWindow(t, 8, 4)
+
+	//The top left pixel of the image is red, the others are blue
+	pixels := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	draw.Draw(pixels, pixels.Bounds(), image.NewUniform(blue), image.Point{}, draw.Src)
+	pixels.SetRGBA(0, 0, red)
+
+	graphics := window.CreateGraphics()
+	graphics.FillRectangle(0, 0, 1, 1, red)
+	graphics.DrawImage(NewImage(pixels), 6, 2)
+	if window.Frames() != 0 {
+		t.Fatal("a frame was presented before Update")
+	}
+	window.Update()
+
+	frame := window.LastFrame()
+	tests := []struct {
+		name string
+		x, y int
+		want color.RGBA
+	}{
+		{"rectangle at the bottom left corner", 0, 3, red},
+		{"top left corner", 0, 0, color.RGBA{A: 255}},
+		{"top left pixel of the image", 6, 0, red},
+		{"bottom left pixel of the image", 6, 1, blue},
+	}
+	for _, test := range tests {
+		if got := frame.RGBAAt(test.x, test.y); got != test.want {
+			t.Errorf("%s: pixel (%d, %d) = %v, want %v", test.name, test.x, test.y, got, test.want)
+		}
+	}
+
+	if window.Frames() != 1 {
+		t.Errorf("frames = %d, want 1", window.Frames())
+	}
+}
+
+// Vetoes the closing of the window while veto is set
+type closeListener struct {
+	veto     bool
+	requests int
+}
+
+func (l *closeListener) CloseRequested() bool {
+	l.requests++
+	return !l.veto
+}
+
+func TestClose(t *testing.T) {
+	window := newWindow(t, 8, 4)
+	listener := &closeListener{veto: true}
+	window.SetCloseListener(listener)
+
+	window.RequestClose()
+	if window.ShouldClose() || listener.requests != 1 {
+		t.Fatal("the window closed even though the listener vetoed it")
+	}
+
+	listener.veto = false
+	window.RequestClose()
+	if !window.ShouldClose() {
+		t.Fatal("the window didn't close when the listener allowed it")
+	}
+
+	//Close doesn't ask the listener
+	window = newWindow(t, 8, 4)
+	window.SetCloseListener(listener)
+	window.Close()
+	if !window.ShouldClose() || listener.requests != 2 {
+		t.Error("Close didn't close the window without asking the listener")
+	}
+}
+
+// Records every input event as text
+type inputListener struct {
+	events []string
+}
+
+func (l *inputListener) KeyEvent(event key.Event) {
+	l.events = append(l.events, fmt.Sprintf("key %d %d", event.Key, event.Action))
+}
+
+func (l *inputListener) CharacterTyped(char rune) {
+	l.events = append(l.events, fmt.Sprintf("char %c", char))
+}
+
+func (l *inputListener) CursorMoved(x int, y int) {
+	l.events = append(l.events, fmt.Sprintf("cursor %d %d", x, y))
+}
+
+func (l *inputListener) ButtonPressed(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	l.events = append(l.events, fmt.Sprintf("pressed %d %d %d %d", button, x, y, modifiers))
+}
+
+func (l *inputListener) ButtonReleased(button cursor.Button, x int, y int, modifiers key.Modifier) {
+	l.events = append(l.events, fmt.Sprintf("released %d %d %d %d", button, x, y, modifiers))
+}
+
+func (l *inputListener) Scrolled(xOffset float64, yOffset float64) {
+	l.events = append(l.events, fmt.Sprintf("scrolled %v %v", xOffset, yOffset))
+}
+
+func TestInputInjection(t *testing.T) {
+	window := newWindow(t, 320, 180)
+	listener := &inputListener{}
+	window.SetKeyListener(listener)
+	window.SetTextListener(listener)
+	window.SetCursorListener(listener)
+	window.SetButtonListener(listener)
+	window.SetScrollListener(listener)
+
+	tests := []struct {
+		name   string
+		inject func()
+		events []string
+	}{
+		{"press key", func() { window.PressKey(key.SPACE) }, []string{"key 1 0"}},
+		{"release key", func() { window.ReleaseKey(key.SPACE) }, []string{"key 1 1"}},
+		{"type text", func() { window.TypeText("hé") }, []string{"char h", "char é"}},
+		{"move cursor", func() { window.MoveCursor(10, 20) }, []string{"cursor 10 20"}},
+		{"move cursor in a larger window", func() {
+			window.Resize(640, 360)
+			window.MoveCursor(10, 20)
+		}, []string{"cursor 5 10"}},
+		{"press button", func() { window.PressButton(cursor.RIGHT, 100, 50, key.MOD_SHIFT) },
+			[]string{fmt.Sprintf("pressed %d 50 25 %d", cursor.RIGHT, key.MOD_SHIFT)}},
+		{"release button", func() { window.ReleaseButton(cursor.RIGHT, 100, 50, 0) },
+			[]string{fmt.Sprintf("released %d 50 25 0", cursor.RIGHT)}},
+		{"scroll", func() { window.Scroll(0, -1.5) }, []string{"scrolled 0 -1.5"}},
+	}
+
+	for _, test := range tests {
+		listener.events = nil
+		test.inject()
+		if fmt.Sprint(listener.events) != fmt.Sprint(test.events) {
+			t.Errorf("%s: got events %v, want %v", test.name, listener.events, test.events)
+		}
+	}
+}
+
+func TestInputWithoutListeners(t *testing.T) {
+	window := newWindow(t, 8, 4)
+
+	//Nothing to deliver the events to, but nothing should fail either
+	window.PressKey(key.SPACE)
+	window.TypeText("a")
+	window.MoveCursor(1, 1)
+	window.PressButton(cursor.LEFT, 1, 1, 0)
+	window.Scroll(0, 1)
+	window.RequestClose()
+	if !window.ShouldClose() {
+		t.Error("the window didn't close without a close listener")
+	}
+}
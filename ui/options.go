@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+)
+
+// DrawOptions changes how Graphics.DrawImageOptions draws an image. The zero value draws the whole image unchanged with
+// its bottom left corner at (0, 0).
+type DrawOptions struct {
+	//Region of the image that is drawn, in its pixels with the origin at the top left corner (like image files and
+	//sprite sheet descriptions); an empty rectangle draws the whole image
+	Source image.Rectangle
+	//Where the origin of the image is drawn
+	X float32
+	Y float32
+	//Point of the image (or of the region), in its own pixels from its bottom left corner, that is drawn at X and Y;
+	//the image is rotated and scaled around it
+	OriginX float32
+	OriginY float32
+	//Counterclockwise rotation, in radians
+	Rotation float32
+	//Mirror the image in place, before it is rotated and scaled
+	FlipX bool
+	FlipY bool
+	//Color multiplied by the colors of the image; nil keeps them unchanged
+	Tint color.Color
+
+	//The scale and the opacity are stored as their difference from 1 (e.g. a scaleX of -0.5 is half the width and a
+	//transparency of 1 is invisible), so the zero value draws the image at its original size and fully opaque while 0
+	//is still a valid scale and opacity. They are unexported so they are only read and written through Scale,
+	//SetScale, Opacity and SetOpacity, which convert them.
+	scaleX       float32
+	scaleY       float32
+	transparency float32
+}
+
+// NewDrawOptions returns the options that draw an image unchanged with its bottom left corner at the given position,
+// like Graphics.DrawImage.
+func NewDrawOptions(x float32, y float32) DrawOptions {
+	return DrawOptions{X: x, Y: y}
+}
+
+// Scale returns how much the image is stretched horizontally and vertically, 1 being its original size.
+func (o DrawOptions) Scale() (float32, float32) {
+	return 1 + o.scaleX, 1 + o.scaleY
+}
+
+// SetScale sets how much the image is stretched horizontally and vertically, 1 being its original size; negative
+// values mirror the image around its origin.
+func (o *DrawOptions) SetScale(x float32, y float32) {
+	o.scaleX = x - 1
+	o.scaleY = y - 1
+}
+
+// Opacity returns the opacity from 0 (invisible) to 1 (unchanged), multiplied by the opacity set in the Graphics.
+func (o DrawOptions) Opacity() float32 {
+	return 1 - o.transparency
+}
+
+// SetOpacity sets the opacity from 0 (invisible) to 1 (unchanged), multiplied by the opacity set in the Graphics.
+func (o *DrawOptions) SetOpacity(opacity float32) {
+	o.transparency = 1 - opacity
+}
+
+// SourceRectangle returns the region of an image drawn by the options, which is the whole image if no region was
+// chosen. The region is clipped to the image, so the parts outside of it (e.g. other images in the same texture) aren't
+// drawn.
+func (o DrawOptions) SourceRectangle(img Image) image.Rectangle {
+	width, height := img.Size()
+	bounds := image.Rect(0, 0, width, height)
+	if o.Source.Empty() {
+		return bounds
+	}
+	return o.Source.Intersect(bounds)
+}
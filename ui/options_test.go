@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestDrawOptionsZeroValue(t *testing.T) {
+	var options DrawOptions
+	if x, y := options.Scale(); x != 1 || y != 1 {
+		t.Errorf("scale = %v, %v, want 1, 1", x, y)
+	}
+	if options.Opacity() != 1 {
+		t.Errorf("opacity = %v, want 1", options.Opacity())
+	}
+
+	options.SetScale(2, 0.5)
+	options.SetOpacity(0)
+	if x, y := options.Scale(); x != 2 || y != 0.5 {
+		t.Errorf("scale = %v, %v, want 2, 0.5", x, y)
+	}
+	if options.Opacity() != 0 {
+		t.Errorf("opacity = %v, want 0", options.Opacity())
+	}
+}
+
+func TestSourceRectangle(t *testing.T) {
+	img := &fakeImage{width: 32, height: 16}
+
+	tests := []struct {
+		name   string
+		source image.Rectangle
+		want   image.Rectangle
+	}{
+		{"whole image", image.Rectangle{}, image.Rect(0, 0, 32, 16)},
+		{"region", image.Rect(8, 0, 16, 8), image.Rect(8, 0, 16, 8)},
+		{"partly outside", image.Rect(24, 8, 40, 24), image.Rect(24, 8, 32, 16)},
+		{"outside", image.Rect(40, 0, 48, 8), image.Rectangle{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := DrawOptions{Source: test.source}
+			if got := options.SourceRectangle(img); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,33 +2,116 @@ package ui
 
 import (
 	"github.com/Hikarikun92/go-game-engine/cursor"
+	"github.com/Hikarikun92/go-game-engine/gamepad"
 	"github.com/Hikarikun92/go-game-engine/key"
 	"github.com/Hikarikun92/go-game-engine/settings"
+	"image"
+	"image/color"
 )
 
 type WindowManager interface {
-	CreateMainWindow(settings *settings.Settings) Window
+	CreateMainWindow(settings *settings.Settings) (Window, error)
 }
 
 type Window interface {
-	SetKeyListener(keyListener key.Listener)
+	SetKeyListener(keyListener key.EventListener)
+	SetTextListener(textListener key.TextListener)
 	SetCursorListener(cursorListener cursor.Listener)
+	SetButtonListener(buttonListener cursor.ButtonListener)
+	SetScrollListener(scrollListener cursor.ScrollListener)
+	SetResizeListener(resizeListener ResizeListener)
+	SetFocusListener(focusListener FocusListener)
+	SetMinimizeListener(minimizeListener MinimizeListener)
+	SetCloseListener(closeListener CloseListener)
+	SetDropListener(dropListener DropListener)
+
+	// SetWindowMode switches between windowed and fullscreen at runtime, keeping the loaded images and the monitor
+	// chosen in the settings.
+	SetWindowMode(mode settings.WindowMode)
+	WindowMode() settings.WindowMode
 
 	CreateImageLoader() ImageLoader
+	CreateGamepadPoller() gamepad.Poller
 	CreateGraphics() Graphics
 	ShouldClose() bool
 	Update()
 	Destroy()
 }
 
+// ImageLoader creates the images of a backend. Wrappers of another ImageLoader must implement CreateSubImage too,
+// usually by forwarding it to the wrapped loader.
 type ImageLoader interface {
-	LoadImage(file string) Image
+	LoadImage(file string) (Image, error)
+	CreateImage(img image.Image) (Image, error)
+	// CreateSubImage returns an image that draws a region of a parent image (in its pixels, with the origin at the top
+	// left corner) without copying it, so both are drawn from the same texture. The sub-image doesn't need to be
+	// unloaded, but it can't be drawn after the original image is unloaded.
+	CreateSubImage(parent Image, region image.Rectangle) (Image, error)
 	UnloadImage(image Image)
 }
 
+// Image is an image created by an ImageLoader and drawn by the Graphics of the same backend. Implementations must
+// report their size.
 type Image interface {
+	//Size of the image in pixels
+	Size() (width int, height int)
 }
 
 type Graphics interface {
 	DrawImage(image Image, x int, y int)
+	//Draws an image transformed by the options, without needing pre-rendered variants of it
+	DrawImageOptions(image Image, options DrawOptions)
+	FillRectangle(x int, y int, width int, height int, color color.Color)
+
+	//The following settings affect everything drawn afterwards, until they are changed again or the next frame starts
+
+	//Moves everything drawn by the given amount
+	SetOffset(x int, y int)
+	//Restricts drawing to the given area of the screen (which is not affected by the offset)
+	SetClip(x int, y int, width int, height int)
+	ClearClip()
+	//Multiplies the opacity of everything drawn, from 0 (invisible) to 1 (unchanged)
+	SetOpacity(opacity float32)
+}
+
+// DrawCallCounter can be implemented by Graphics to report how many images and rectangles were drawn so far in the
+// frame.
+type DrawCallCounter interface {
+	DrawCalls() int
+}
+
+// BatchCounter can be implemented by the Graphics of backends that group the draws into batches, returning how many
+// batches were sent to the GPU in the current frame (each one being a single draw call).
+type BatchCounter interface {
+	Batches() int
+}
+
+// ResizeListener receives the new size of the window, in pixels, whenever it changes. The logical resolution of the
+// game is not affected.
+type ResizeListener interface {
+	WindowResized(width int, height int)
+}
+
+// FocusListener is notified when the window gains or loses the keyboard focus, e.g. when the player switches to another
+// window.
+type FocusListener interface {
+	FocusGained()
+	FocusLost()
+}
+
+// MinimizeListener is notified when the window is minimized and restored.
+type MinimizeListener interface {
+	Minimized()
+	Restored()
+}
+
+// CloseListener is asked whether the window should close when the player tries to close it. Returning false keeps it
+// open, e.g. to ask about unsaved changes first.
+type CloseListener interface {
+	CloseRequested() bool
+}
+
+// DropListener receives the paths of the files dropped by the player on the window.
+type DropListener interface {
+	FilesDropped(paths []string)
 }
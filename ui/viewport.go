@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"math"
+)
+
+// Viewport is the area of a window where the game is drawn, when the window's size is different from the game's
+// logical resolution. Its position is in pixels with the origin at the bottom left corner of the window.
+type Viewport struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+
+	LogicalWidth  int
+	LogicalHeight int
+	WindowWidth   int
+	WindowHeight  int
+}
+
+func NewViewport(scaling settings.Scaling, logicalWidth int, logicalHeight int, windowWidth int, windowHeight int) Viewport {
+	viewport := Viewport{
+		LogicalWidth:  logicalWidth,
+		LogicalHeight: logicalHeight,
+		WindowWidth:   windowWidth,
+		WindowHeight:  windowHeight,
+	}
+
+	//E.g. minimized windows
+	if logicalWidth <= 0 || logicalHeight <= 0 || windowWidth <= 0 || windowHeight <= 0 {
+		return viewport
+	}
+
+	if scaling == settings.STRETCH {
+		viewport.Width = windowWidth
+		viewport.Height = windowHeight
+		return viewport
+	}
+
+	scale := math.Min(float64(windowWidth)/float64(logicalWidth), float64(windowHeight)/float64(logicalHeight))
+	if scaling == settings.INTEGER && scale >= 1 {
+		scale = math.Floor(scale)
+	}
+
+	viewport.Width = int(math.Round(float64(logicalWidth) * scale))
+	viewport.Height = int(math.Round(float64(logicalHeight) * scale))
+	viewport.X = (windowWidth - viewport.Width) / 2
+	viewport.Y = (windowHeight - viewport.Height) / 2
+	return viewport
+}
+
+// ToLogical converts a position in the window (such as the cursor's) to the logical resolution. Both positions have the
+// origin at the top left corner, like the events coming from the operating system; the result may be outside the
+// logical resolution if the position is over the black bars.
+func (v Viewport) ToLogical(x float64, y float64) (int, int) {
+	if v.Width <= 0 || v.Height <= 0 {
+		return 0, 0
+	}
+
+	top := v.WindowHeight - v.Y - v.Height
+	logicalX := (x - float64(v.X)) * float64(v.LogicalWidth) / float64(v.Width)
+	logicalY := (y - float64(top)) * float64(v.LogicalHeight) / float64(v.Height)
+	return int(math.Floor(logicalX)), int(math.Floor(logicalY))
+}
+
+// ToWindow converts a rectangle in the logical resolution to the window's pixels, both with the origin at the bottom
+// left corner.
+func (v Viewport) ToWindow(x int, y int, width int, height int) (int, int, int, int) {
+	if v.LogicalWidth <= 0 || v.LogicalHeight <= 0 {
+		return 0, 0, 0, 0
+	}
+
+	scaleX := float64(v.Width) / float64(v.LogicalWidth)
+	scaleY := float64(v.Height) / float64(v.LogicalHeight)
+
+	left := v.X + int(math.Round(float64(x)*scaleX))
+	bottom := v.Y + int(math.Round(float64(y)*scaleY))
+	right := v.X + int(math.Round(float64(x+width)*scaleX))
+	top := v.Y + int(math.Round(float64(y+height)*scaleY))
+	return left, bottom, right - left, top - bottom
+}
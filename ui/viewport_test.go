@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"github.com/Hikarikun92/go-game-engine/settings"
+	"testing"
+)
+
+func TestNewViewport(t *testing.T) {
+	tests := []struct {
+		name                      string
+		scaling                   settings.Scaling
+		windowWidth, windowHeight int
+		x, y, width, height       int
+	}{
+		{"fit with the same aspect ratio", settings.FIT, 1280, 720, 0, 0, 1280, 720},
+		{"fit with bars", settings.FIT, 1000, 720, 0, 78, 1000, 563},
+		{"stretch", settings.STRETCH, 1000, 720, 0, 0, 1000, 720},
+		{"integer", settings.INTEGER, 1000, 720, 20, 90, 960, 540},
+		{"integer smaller than the resolution", settings.INTEGER, 160, 90, 0, 0, 160, 90},
+		{"minimized", settings.FIT, 0, 0, 0, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := NewViewport(test.scaling, 320, 180, test.windowWidth, test.windowHeight)
+			if v.X != test.x || v.Y != test.y || v.Width != test.width || v.Height != test.height {
+				t.Errorf("got %d,%d %dx%d, want %d,%d %dx%d", v.X, v.Y, v.Width, v.Height,
+					test.x, test.y, test.width, test.height)
+			}
+		})
+	}
+}
+
+func TestViewportToLogical(t *testing.T) {
+	//960x540 centered in a 1000x720 window, with the origin of the events at the top left corner
+	v := NewViewport(settings.INTEGER, 320, 180, 1000, 720)
+
+	tests := []struct {
+		windowX, windowY float64
+		x, y             int
+	}{
+		{20, 90, 0, 0},
+		{500, 360, 160, 90},
+		{980, 630, 320, 180},
+		{22.9, 92.9, 0, 0},
+		{10, 0, -4, -30},
+	}
+
+	for _, test := range tests {
+		x, y := v.ToLogical(test.windowX, test.windowY)
+		if x != test.x || y != test.y {
+			t.Errorf("ToLogical(%v, %v) = %d, %d, want %d, %d", test.windowX, test.windowY, x, y, test.x, test.y)
+		}
+	}
+
+	if x, y := NewViewport(settings.FIT, 320, 180, 0, 0).ToLogical(10, 10); x != 0 || y != 0 {
+		t.Errorf("ToLogical on a minimized window = %d, %d, want 0, 0", x, y)
+	}
+}
+
+func TestViewportToWindow(t *testing.T) {
+	v := NewViewport(settings.INTEGER, 320, 180, 1000, 720)
+
+	tests := []struct {
+		logical [4]int
+		window  [4]int
+	}{
+		{[4]int{0, 0, 320, 180}, [4]int{20, 90, 960, 540}},
+		{[4]int{10, 20, 5, 5}, [4]int{50, 150, 15, 15}},
+	}
+
+	for _, test := range tests {
+		x, y, width, height := v.ToWindow(test.logical[0], test.logical[1], test.logical[2], test.logical[3])
+		if got := [4]int{x, y, width, height}; got != test.window {
+			t.Errorf("ToWindow%v = %v, want %v", test.logical, got, test.window)
+		}
+	}
+}